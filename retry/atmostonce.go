@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// unknownOutcomeError marks the error of an attempt whose effect is unknown.
+type unknownOutcomeError struct {
+	err error
+}
+
+func (e *unknownOutcomeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unknownOutcomeError) Unwrap() error {
+	return e.err
+}
+
+// OutcomeUnknown wraps err to tell WithAtMostOnce that the attempt may have taken effect nevertheless, e.g. because
+// a request was sent but its response got lost. The returned error unwraps to err. OutcomeUnknown returns nil if err
+// is nil.
+func OutcomeUnknown(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &unknownOutcomeError{err: err}
+}
+
+// IsOutcomeUnknown reports whether err was marked with OutcomeUnknown or is the error of an attempt that exceeded the
+// timeout set with WithAttemptTimeout, as fn may have completed its work before noticing the cancellation.
+func IsOutcomeUnknown(err error) bool {
+	var unknownErr *unknownOutcomeError
+	return errors.As(err, &unknownErr) || errors.Is(err, ErrAttemptTimeout)
+}
+
+// WithAtMostOnce guards non-idempotent workloads against being executed twice. Before an attempt whose outcome is
+// unknown according to IsOutcomeUnknown is retried, check is called to find out whether it took effect after all,
+// e.g. by looking up the resource the attempt should have created:
+//   - If check reports success, the retry loop ends successfully without another attempt.
+//   - If check reports no success, the workload is retried as usual.
+//   - If check fails, the retry loop ends with an error wrapping both the error of check and the one of the attempt.
+//
+// If check is nil, attempts with an unknown outcome are never retried and their error is returned. Attempts failing
+// with other errors are retried as usual either way.
+func WithAtMostOnce(check func(ctx context.Context) (succeeded bool, err error)) Option {
+	return func(p *policy) {
+		p.atMostOnce = true
+		p.checkOutcome = check
+	}
+}
+
+// resolveUnknownOutcome decides whether the failed attempt with the given number may be retried if p guards against
+// repeated executions. It returns whether the attempt succeeded after all and the error ending the retry loop, if
+// any.
+func (p *policy) resolveUnknownOutcome(ctx context.Context, attempt int, err error) (bool, error) {
+	if !p.atMostOnce || !IsOutcomeUnknown(err) {
+		return false, nil
+	}
+	if p.checkOutcome == nil {
+		return false, err
+	}
+
+	succeeded, checkErr := p.checkOutcome(ctx)
+	if checkErr != nil {
+		return false, fmt.Errorf("failed to check the outcome of attempt %d: %w", attempt, errors.Join(checkErr, err))
+	}
+	return succeeded, nil
+}
@@ -0,0 +1,126 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OutcomeUnknown(t *testing.T) {
+	t.Run("should mark error", func(t *testing.T) {
+		// when
+		err := fmt.Errorf("create: %w", OutcomeUnknown(assert.AnError))
+
+		// then
+		assert.True(t, IsOutcomeUnknown(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, "create: "+assert.AnError.Error(), err.Error())
+	})
+	t.Run("should return nil for nil", func(t *testing.T) {
+		assert.NoError(t, OutcomeUnknown(nil))
+	})
+	t.Run("should recognize attempt timeout", func(t *testing.T) {
+		assert.True(t, IsOutcomeUnknown(fmt.Errorf("%w: %w", ErrAttemptTimeout, context.DeadlineExceeded)))
+		assert.False(t, IsOutcomeUnknown(assert.AnError))
+	})
+}
+
+func Test_WithAtMostOnce(t *testing.T) {
+	t.Run("should end successfully if check reports success", func(t *testing.T) {
+		// given
+		checks := 0
+		sut := New(WithBackoff(Constant(0)), WithAtMostOnce(func(ctx context.Context) (bool, error) {
+			checks++
+			return true, nil
+		}))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return OutcomeUnknown(assert.AnError)
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, 1, checks)
+	})
+	t.Run("should retry if check reports no success", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithAtMostOnce(func(ctx context.Context) (bool, error) {
+			return false, nil
+		}))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return OutcomeUnknown(assert.AnError)
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+	t.Run("should not retry timed-out attempt without check", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithAttemptTimeout(time.Millisecond), WithAtMostOnce(nil))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// then
+		assert.ErrorIs(t, err, ErrAttemptTimeout)
+		assert.Equal(t, 1, attempts)
+	})
+	t.Run("should fail if check fails", func(t *testing.T) {
+		// given
+		checkErr := errors.New("lookup failed")
+		sut := New(WithBackoff(Constant(0)), WithAtMostOnce(func(ctx context.Context) (bool, error) {
+			return false, checkErr
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return OutcomeUnknown(assert.AnError)
+		})
+
+		// then
+		assert.ErrorIs(t, err, checkErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to check the outcome of attempt 1")
+	})
+	t.Run("should retry other errors without check", func(t *testing.T) {
+		// given
+		checks := 0
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithAtMostOnce(func(ctx context.Context) (bool, error) {
+			checks++
+			return false, nil
+		}))
+		attempts := 0
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, 0, checks)
+	})
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAttemptTimeout is wrapped by the error of an attempt that exceeded the timeout set with WithAttemptTimeout.
+var ErrAttemptTimeout = errors.New("attempt timed out")
+
+// WithAttemptTimeout limits the duration of a single attempt. Once the timeout passes, the context of the attempt is
+// canceled with context.DeadlineExceeded, so that fn can stop its work. The next attempt is not started before fn
+// has returned, even if it ignores the cancellation, so that non-reentrant operations never overlap. The error of a
+// timed-out attempt wraps ErrAttemptTimeout and is classified like any other error. The timeout runs on the wall
+// clock regardless of WithClock. A timeout of zero or less disables it.
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(p *policy) {
+		p.attemptTimeout = timeout
+	}
+}
+
+// attempt executes the described attempt of fn, bounded by the attempt timeout of p if it is set, and invokes the
+// hooks around it. The hooks and fn share a context carrying fresh AttemptValues and the AttemptInfo.
+func (p *policy) attempt(ctx context.Context, info AttemptInfo, fn func(ctx context.Context) error) error {
+	ctx = &attemptContext{Context: ctx, scope: attemptScope{info: info, captureStacks: p.stackTraces}}
+
+	var err error
+	p.labeled(ctx, info.Number, func(ctx context.Context) {
+		if p.beforeAttempt != nil {
+			err = p.beforeAttempt(ctx, info.Number)
+		}
+		if err == nil {
+			err = p.attemptWithTimeout(ctx, fn)
+		}
+		if err != nil && p.cleanup != nil {
+			p.cleanup(ctx, err)
+		}
+	})
+	return err
+}
+
+func (p *policy) attemptWithTimeout(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.attemptTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeoutCause(ctx, p.attemptTimeout, ErrAttemptTimeout)
+	defer cancel()
+	err := fn(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(context.Cause(attemptCtx), ErrAttemptTimeout) && !errors.Is(err, ErrAttemptTimeout) {
+		return fmt.Errorf("%w after %s: %w", ErrAttemptTimeout, p.attemptTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithAttemptTimeout(t *testing.T) {
+	t.Run("should cancel slow attempt and retry", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithAttemptTimeout(10*time.Millisecond))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+	t.Run("should wrap error of timed out attempt", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithAttemptTimeout(time.Millisecond))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.ErrorIs(t, err, ErrAttemptTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("should not overlap attempts ignoring cancellation", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithAttemptTimeout(time.Millisecond))
+		running := &atomic.Int32{}
+		overlapped := false
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			if running.Add(1) > 1 {
+				overlapped = true
+			}
+			defer running.Add(-1)
+			time.Sleep(20 * time.Millisecond)
+			return assert.AnError
+		})
+
+		// then
+		assert.False(t, overlapped)
+	})
+	t.Run("should not wrap cancellation of parent context", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithAttemptTimeout(time.Hour))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrAttemptTimeout)
+	})
+}
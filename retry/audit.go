@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Auditor receives a report once per completed retry loop. This allows platform teams to ship mandatory audit
+// records of critical operations without instrumenting every call site. Implementations must be safe for
+// concurrent use and should return quickly as they are called synchronously at the end of the loop.
+type Auditor interface {
+	// Audit is called with the context of the retry loop, the operation's name set with WithOperation and the
+	// report of the loop.
+	Audit(ctx context.Context, operation string, report *Report)
+}
+
+// AuditorFunc adapts an ordinary function to the Auditor interface.
+type AuditorFunc func(ctx context.Context, operation string, report *Report)
+
+// Audit calls f(ctx, operation, report).
+func (f AuditorFunc) Audit(ctx context.Context, operation string, report *Report) {
+	f(ctx, operation, report)
+}
+
+type auditorHolder struct {
+	auditor Auditor
+}
+
+var defaultAuditor atomic.Pointer[auditorHolder]
+
+// SetDefaultAuditor sets the Auditor used by all Retriers without an auditor configured with WithAuditor. Passing
+// nil disables auditing again.
+func SetDefaultAuditor(auditor Auditor) {
+	defaultAuditor.Store(&auditorHolder{auditor: auditor})
+}
+
+// DefaultAuditor returns the Auditor set with SetDefaultAuditor or nil.
+func DefaultAuditor() Auditor {
+	holder := defaultAuditor.Load()
+	if holder == nil {
+		return nil
+	}
+	return holder.auditor
+}
+
+// WithAuditor sets the Auditor receiving the report of every retry loop. It takes precedence over the default
+// auditor.
+func WithAuditor(auditor Auditor) Option {
+	return func(p *policy) {
+		p.auditor = auditor
+	}
+}
+
+// WithOperation names the operation the Retrier executes, e.g. "backup" or "restore". The name is passed on to
+// the Auditor.
+func WithOperation(name string) Option {
+	return func(p *policy) {
+		p.operation = name
+	}
+}
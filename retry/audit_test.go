@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditor struct {
+	operations []string
+	reports    []*Report
+}
+
+func (a *recordingAuditor) Audit(_ context.Context, operation string, report *Report) {
+	a.operations = append(a.operations, operation)
+	a.reports = append(a.reports, report)
+}
+
+func Test_WithAuditor(t *testing.T) {
+	// given
+	auditor := &recordingAuditor{}
+	sut := New(WithAuditor(auditor), WithOperation("backup"), WithMaxTries(2), WithBackoff(Constant(time.Millisecond)))
+
+	// when
+	err := sut.Do(context.Background(), func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	// then
+	require.Error(t, err)
+	require.Len(t, auditor.reports, 1)
+	assert.Equal(t, []string{"backup"}, auditor.operations)
+	assert.Equal(t, OutcomeExhausted, auditor.reports[0].Outcome)
+	assert.Len(t, auditor.reports[0].Attempts, 2)
+	assert.Equal(t, err, auditor.reports[0].Err)
+}
+
+func Test_SetDefaultAuditor(t *testing.T) {
+	t.Run("should audit retriers without auditor", func(t *testing.T) {
+		// given
+		auditor := &recordingAuditor{}
+		SetDefaultAuditor(auditor)
+		defer SetDefaultAuditor(nil)
+		sut := New(WithOperation("restore"))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, auditor.reports, 1)
+		assert.Equal(t, "restore", auditor.operations[0])
+		assert.Equal(t, OutcomeSucceeded, auditor.reports[0].Outcome)
+	})
+	t.Run("should prefer configured auditor", func(t *testing.T) {
+		// given
+		defaultAuditor := &recordingAuditor{}
+		SetDefaultAuditor(defaultAuditor)
+		defer SetDefaultAuditor(nil)
+		auditor := &recordingAuditor{}
+		sut := New(WithAuditor(auditor))
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		assert.Empty(t, defaultAuditor.reports)
+		assert.Len(t, auditor.reports, 1)
+	})
+	t.Run("should not audit after reset", func(t *testing.T) {
+		// given
+		SetDefaultAuditor(nil)
+
+		// then
+		assert.Nil(t, DefaultAuditor())
+	})
+}
+
+func Test_AuditorFunc(t *testing.T) {
+	// given
+	var actual string
+	sut := AuditorFunc(func(_ context.Context, operation string, _ *Report) {
+		actual = operation
+	})
+
+	// when
+	sut.Audit(context.Background(), "op", &Report{})
+
+	// then
+	assert.Equal(t, "op", actual)
+}
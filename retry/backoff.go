@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// defaultBackoff is used by a Retrier without any backoff configured.
+var defaultBackoff = Exponential{Initial: 1500 * time.Millisecond, Factor: 1.5}
+
+// Backoff computes the delay between two attempts.
+type Backoff interface {
+	// Delay returns the time to wait after the given attempt. The first attempt is numbered 1.
+	Delay(attempt int) time.Duration
+}
+
+// BackoffFunc adapts an ordinary function to the Backoff interface.
+type BackoffFunc func(attempt int) time.Duration
+
+// Delay returns f(attempt).
+func (f BackoffFunc) Delay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// Constant returns a Backoff that always waits for the same delay.
+func Constant(delay time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration {
+		return delay
+	})
+}
+
+// Exponential is a Backoff that starts with Initial and multiplies the delay by Factor after every attempt.
+type Exponential struct {
+	// Initial is the delay after the first attempt.
+	Initial time.Duration
+	// Factor is the multiplier applied to the delay after every further attempt.
+	Factor float64
+	// Max caps the delay if greater than zero.
+	Max time.Duration
+}
+
+// Delay returns Initial * Factor^(attempt-1), capped by Max.
+func (e Exponential) Delay(attempt int) time.Duration {
+	delay := float64(e.Initial) * math.Pow(e.Factor, float64(attempt-1))
+	if e.Max > 0 && delay > float64(e.Max) {
+		return e.Max
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(delay)
+}
+
+// WithInitialDelay sets the delay after the first attempt of the exponential backoff. If a different backoff
+// strategy was configured before, it is replaced by the default exponential backoff.
+func WithInitialDelay(delay time.Duration) Option {
+	return func(p *policy) {
+		exponential := p.exponential()
+		exponential.Initial = delay
+		p.backoff = exponential
+	}
+}
+
+// WithBackoffFactor sets the factor the delay of the exponential backoff grows by after every attempt. A factor of
+// 1 results in a constant delay. If a different backoff strategy was configured before, it is replaced by the
+// default exponential backoff.
+func WithBackoffFactor(factor float64) Option {
+	return func(p *policy) {
+		exponential := p.exponential()
+		exponential.Factor = factor
+		p.backoff = exponential
+	}
+}
+
+// WithMaxDelay caps the delay of the exponential backoff. If a different backoff strategy was configured before, it
+// is replaced by the default exponential backoff.
+func WithMaxDelay(delay time.Duration) Option {
+	return func(p *policy) {
+		exponential := p.exponential()
+		exponential.Max = delay
+		p.backoff = exponential
+	}
+}
+
+// exponential returns the configured exponential backoff or the default one if a different strategy is used.
+func (p *policy) exponential() Exponential {
+	if exponential, ok := p.backoff.(Exponential); ok {
+		return exponential
+	}
+	return defaultBackoff
+}
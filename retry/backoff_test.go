@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Constant(t *testing.T) {
+	sut := Constant(time.Second)
+	assert.Equal(t, time.Second, sut.Delay(1))
+	assert.Equal(t, time.Second, sut.Delay(100))
+}
+
+func Test_Exponential_Delay(t *testing.T) {
+	t.Run("should grow by factor", func(t *testing.T) {
+		sut := Exponential{Initial: 100 * time.Millisecond, Factor: 2}
+		assert.Equal(t, 100*time.Millisecond, sut.Delay(1))
+		assert.Equal(t, 200*time.Millisecond, sut.Delay(2))
+		assert.Equal(t, 800*time.Millisecond, sut.Delay(4))
+	})
+	t.Run("should be capped", func(t *testing.T) {
+		sut := Exponential{Initial: 100 * time.Millisecond, Factor: 2, Max: 300 * time.Millisecond}
+		assert.Equal(t, 200*time.Millisecond, sut.Delay(2))
+		assert.Equal(t, 300*time.Millisecond, sut.Delay(3))
+		assert.Equal(t, 300*time.Millisecond, sut.Delay(50))
+	})
+	t.Run("should not overflow", func(t *testing.T) {
+		sut := Exponential{Initial: time.Second, Factor: 10}
+		assert.Equal(t, time.Duration(math.MaxInt64), sut.Delay(1000))
+	})
+}
+
+func Test_WithInitialDelay(t *testing.T) {
+	t.Run("should adjust default backoff", func(t *testing.T) {
+		p := defaultPolicy()
+		WithInitialDelay(time.Second)(&p)
+		assert.Equal(t, Exponential{Initial: time.Second, Factor: 1.5}, p.backoff)
+	})
+	t.Run("should replace other backoff", func(t *testing.T) {
+		p := defaultPolicy()
+		WithBackoff(Constant(time.Minute))(&p)
+		WithInitialDelay(time.Second)(&p)
+		assert.Equal(t, Exponential{Initial: time.Second, Factor: 1.5}, p.backoff)
+	})
+}
+
+func Test_WithBackoffFactor(t *testing.T) {
+	p := defaultPolicy()
+	WithInitialDelay(100 * time.Millisecond)(&p)
+	WithBackoffFactor(3)(&p)
+	assert.Equal(t, Exponential{Initial: 100 * time.Millisecond, Factor: 3}, p.backoff)
+	assert.Equal(t, 900*time.Millisecond, p.backoff.Delay(3))
+}
+
+func Test_WithMaxDelay(t *testing.T) {
+	p := defaultPolicy()
+	WithBackoffFactor(2)(&p)
+	WithMaxDelay(5 * time.Second)(&p)
+	assert.Equal(t, Exponential{Initial: 1500 * time.Millisecond, Factor: 2, Max: 5 * time.Second}, p.backoff)
+	assert.Equal(t, 5*time.Second, p.backoff.Delay(10))
+}
@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is returned by Breaker.Allow while the breaker rejects calls.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerState describes whether a Breaker lets calls pass.
+type BreakerState int
+
+const (
+	// BreakerClosed lets all calls pass.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all calls.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe call pass to find out whether the endpoint recovered.
+	BreakerHalfOpen
+)
+
+// Breaker is a circuit breaker. It opens after a number of consecutive failures, rejects calls for a while and
+// afterward lets a single probe pass. A successful probe closes the breaker again, a failed one reopens it.
+// A Breaker is safe for concurrent use.
+type Breaker struct {
+	threshold int
+	openFor   time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker creates a Breaker opening after threshold consecutive failures for the duration openFor.
+func NewBreaker(threshold int, openFor time.Duration) *Breaker {
+	return &Breaker{
+		threshold: max(threshold, 1),
+		openFor:   openFor,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrBreakerOpen if the call must not be executed. Otherwise, the outcome of the call has to be
+// reported with Success or Failure.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case BreakerOpen:
+		return ErrBreakerOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrBreakerOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	}
+	return nil
+}
+
+// Success reports a successful call and closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure reports a failed call. It opens the breaker if the threshold is reached or the call was a probe.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.currentState()
+}
+
+// Pressure returns 1 while the breaker is open or half-open and otherwise the share of the consecutive failures
+// needed to open it.
+func (b *Breaker) Pressure() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.currentState() != BreakerClosed {
+		return 1
+	}
+	return float64(b.failures) / float64(b.threshold)
+}
+
+func (b *Breaker) currentState() BreakerState {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openFor {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// BreakerRegistry maintains one Breaker per key, e.g. per host or endpoint, so that a single dead endpoint does
+// not affect calls to other endpoints. A BreakerRegistry is safe for concurrent use.
+type BreakerRegistry struct {
+	threshold int
+	openFor   time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewBreakerRegistry creates a BreakerRegistry whose breakers open after threshold consecutive failures for the
+// duration openFor.
+func NewBreakerRegistry(threshold int, openFor time.Duration) *BreakerRegistry {
+	return &BreakerRegistry{
+		threshold: threshold,
+		openFor:   openFor,
+		breakers:  map[string]*Breaker{},
+	}
+}
+
+// Get returns the Breaker for the given key and creates it if necessary.
+func (r *BreakerRegistry) Get(key string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breaker, ok := r.breakers[key]
+	if !ok {
+		breaker = NewBreaker(r.threshold, r.openFor)
+		r.breakers[key] = breaker
+	}
+	return breaker
+}
+
+// Pressure returns the highest pressure of all breakers of the registry, so that a single failing endpoint is
+// noticed. Callers caring about individual endpoints use the Breaker returned by Get instead.
+func (r *BreakerRegistry) Pressure() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pressure := 0.0
+	for _, breaker := range r.breakers {
+		pressure = max(pressure, breaker.Pressure())
+	}
+	return pressure
+}
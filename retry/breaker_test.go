@@ -0,0 +1,165 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBreaker(threshold int, openFor time.Duration) (*Breaker, *time.Time) {
+	now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(threshold, openFor)
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func Test_Breaker(t *testing.T) {
+	t.Run("should open after threshold", func(t *testing.T) {
+		// given
+		sut, _ := newTestBreaker(2, time.Minute)
+
+		// when
+		sut.Failure()
+		afterOne := sut.Allow()
+		sut.Failure()
+		afterTwo := sut.Allow()
+
+		// then
+		assert.NoError(t, afterOne)
+		assert.ErrorIs(t, afterTwo, ErrBreakerOpen)
+		assert.Equal(t, BreakerOpen, sut.State())
+	})
+	t.Run("should reset failures on success", func(t *testing.T) {
+		// given
+		sut, _ := newTestBreaker(2, time.Minute)
+
+		// when
+		sut.Failure()
+		sut.Success()
+		sut.Failure()
+
+		// then
+		assert.NoError(t, sut.Allow())
+		assert.Equal(t, BreakerClosed, sut.State())
+	})
+	t.Run("should admit a single probe after open duration", func(t *testing.T) {
+		// given
+		sut, now := newTestBreaker(1, time.Minute)
+		sut.Failure()
+
+		// when
+		*now = now.Add(time.Minute)
+		state := sut.State()
+		probe := sut.Allow()
+		second := sut.Allow()
+
+		// then
+		assert.Equal(t, BreakerHalfOpen, state)
+		assert.NoError(t, probe)
+		assert.ErrorIs(t, second, ErrBreakerOpen)
+	})
+	t.Run("should close after successful probe", func(t *testing.T) {
+		// given
+		sut, now := newTestBreaker(1, time.Minute)
+		sut.Failure()
+		*now = now.Add(time.Minute)
+		require.NoError(t, sut.Allow())
+
+		// when
+		sut.Success()
+
+		// then
+		assert.Equal(t, BreakerClosed, sut.State())
+		assert.NoError(t, sut.Allow())
+	})
+	t.Run("should reopen after failed probe", func(t *testing.T) {
+		// given
+		sut, now := newTestBreaker(3, time.Minute)
+		sut.Failure()
+		sut.Failure()
+		sut.Failure()
+		*now = now.Add(time.Minute)
+		require.NoError(t, sut.Allow())
+
+		// when
+		sut.Failure()
+
+		// then
+		assert.Equal(t, BreakerOpen, sut.State())
+		assert.ErrorIs(t, sut.Allow(), ErrBreakerOpen)
+	})
+}
+
+func Test_BreakerRegistry_Get(t *testing.T) {
+	// given
+	sut := NewBreakerRegistry(1, time.Minute)
+
+	// when
+	first := sut.Get("a")
+	again := sut.Get("a")
+	other := sut.Get("b")
+	first.Failure()
+
+	// then
+	assert.Same(t, first, again)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, BreakerOpen, again.State())
+	assert.Equal(t, BreakerClosed, other.State())
+}
+
+func Test_Breaker_Pressure(t *testing.T) {
+	t.Run("should grow with consecutive failures", func(t *testing.T) {
+		// given
+		sut, _ := newTestBreaker(4, time.Minute)
+
+		// when
+		before := sut.Pressure()
+		sut.Failure()
+		sut.Failure()
+
+		// then
+		assert.Equal(t, 0.0, before)
+		assert.Equal(t, 0.5, sut.Pressure())
+	})
+	t.Run("should be full while open and half-open", func(t *testing.T) {
+		// given
+		sut, now := newTestBreaker(1, time.Minute)
+
+		// when
+		sut.Failure()
+		open := sut.Pressure()
+		*now = now.Add(time.Minute)
+		halfOpen := sut.Pressure()
+
+		// then
+		assert.Equal(t, 1.0, open)
+		assert.Equal(t, 1.0, halfOpen)
+	})
+	t.Run("should drop after success", func(t *testing.T) {
+		// given
+		sut, _ := newTestBreaker(2, time.Minute)
+		sut.Failure()
+
+		// when
+		sut.Success()
+
+		// then
+		assert.Equal(t, 0.0, sut.Pressure())
+	})
+}
+
+func Test_BreakerRegistry_Pressure(t *testing.T) {
+	// given
+	sut := NewBreakerRegistry(4, time.Minute)
+	sut.Get("a").Failure()
+	sut.Get("b").Failure()
+	sut.Get("b").Failure()
+
+	// when
+	actual := sut.Pressure()
+
+	// then
+	assert.Equal(t, 0.5, actual)
+}
@@ -0,0 +1,56 @@
+package retry
+
+import "time"
+
+// Decision is the verdict of a Classifier about a failed attempt.
+type Decision struct {
+	// Retry tells whether the workload is executed again.
+	Retry bool
+	// Delay replaces the delay of the backoff before the next attempt if it is greater than zero.
+	Delay time.Duration
+}
+
+var (
+	// DecisionRetry retries the workload after the delay of the backoff.
+	DecisionRetry = Decision{Retry: true}
+	// DecisionAbort ends the retry loop and returns the error unchanged.
+	DecisionAbort = Decision{}
+)
+
+// RetryAfter retries the workload after the given delay instead of the one of the backoff, e.g. if the error
+// states when the failure is expected to be over.
+func RetryAfter(delay time.Duration) Decision {
+	return Decision{Retry: true, Delay: delay}
+}
+
+// Classifier decides how to proceed after a failed attempt. Errors wrapped with Abort are never passed to it.
+type Classifier interface {
+	// Classify returns the decision for the error of a failed attempt.
+	Classify(err error) Decision
+}
+
+// ClassifierFunc adapts an ordinary function to the Classifier interface.
+type ClassifierFunc func(err error) Decision
+
+// Classify returns f(err).
+func (f ClassifierFunc) Classify(err error) Decision {
+	return f(err)
+}
+
+// Predicate adapts a function reporting whether an error is retriable, like TestableRetryFunc, to a Classifier.
+func Predicate(retriable func(error) bool) Classifier {
+	return ClassifierFunc(func(err error) Decision {
+		if retriable(err) {
+			return DecisionRetry
+		}
+		return DecisionAbort
+	})
+}
+
+// WithClassifier sets the Classifier deciding how to proceed after a failed attempt. It replaces the function set
+// with WithRetriable and allows richer decisions like error-specific delays.
+func WithClassifier(classifier Classifier) Option {
+	return func(p *policy) {
+		p.classifier = classifier
+	}
+}
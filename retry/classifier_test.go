@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithClassifier(t *testing.T) {
+	t.Run("should use delay of decision", func(t *testing.T) {
+		// given
+		errThrottled := errors.New("throttled")
+		classifier := ClassifierFunc(func(err error) Decision {
+			if errors.Is(err, errThrottled) {
+				return RetryAfter(2 * time.Millisecond)
+			}
+			return DecisionRetry
+		})
+		sut := New(WithClassifier(classifier), WithBackoff(Constant(time.Millisecond)), WithMaxTries(3))
+		calls := 0
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return errThrottled
+			}
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		require.Len(t, report.Attempts, 3)
+		assert.Equal(t, 2*time.Millisecond, report.Attempts[0].Delay)
+		assert.Equal(t, time.Millisecond, report.Attempts[1].Delay)
+	})
+	t.Run("should stop on abort decision", func(t *testing.T) {
+		// given
+		sut := New(WithClassifier(ClassifierFunc(func(err error) Decision {
+			return DecisionAbort
+		})))
+		calls := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should not classify aborted errors", func(t *testing.T) {
+		// given
+		classified := false
+		sut := New(WithClassifier(ClassifierFunc(func(err error) Decision {
+			classified = true
+			return DecisionRetry
+		})))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.False(t, classified)
+	})
+}
+
+func Test_Predicate(t *testing.T) {
+	// given
+	sut := Predicate(func(err error) bool {
+		return errors.Is(err, assert.AnError)
+	})
+
+	// then
+	assert.Equal(t, DecisionRetry, sut.Classify(assert.AnError))
+	assert.Equal(t, DecisionAbort, sut.Classify(errors.New("other")))
+}
@@ -0,0 +1,112 @@
+// Package retry mirrors the API of k8s.io/client-go/util/retry but is backed by the Retrier of
+// github.com/cloudogu/retry-lib/retry. Existing code migrates by swapping the import path:
+//
+//	import "github.com/cloudogu/retry-lib/retry/clientgo"
+//
+// Both OnError and RetryOnConflict accept additional options of the retry-lib to opt into its further features.
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// DefaultRetry is the recommended retry for a conflict where multiple clients
+// are making changes to the same resource.
+var DefaultRetry = wait.Backoff{
+	Steps:    5,
+	Duration: 10 * time.Millisecond,
+	Factor:   1.0,
+	Jitter:   0.1,
+}
+
+// DefaultBackoff is the recommended backoff for a conflict where a client
+// may be attempting to make an unrelated modification to a resource under
+// active management by one or more controllers.
+var DefaultBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 10 * time.Millisecond,
+	Factor:   5.0,
+	Jitter:   0.1,
+}
+
+// OnError retries fn as long as it returns an error considered retriable. backoff defines the maximum number of
+// attempts and the delays in between exactly like k8s.io/client-go/util/retry.OnError does, including the final
+// delay before giving up once the delay exceeds backoff.Cap. If the attempts are exhausted, the last error is
+// returned unwrapped.
+func OnError(backoff wait.Backoff, retriable func(error) bool, fn func() error, opts ...retry.Option) error {
+	maxTries, idleDelay := maxAttempts(backoff)
+	if maxTries == 0 {
+		// wait.ExponentialBackoff never evaluates its condition without steps.
+		return nil
+	}
+
+	retrier := retry.New(append([]retry.Option{
+		retry.WithMaxTries(maxTries),
+		retry.WithLimit(0),
+		retry.WithBackoff(&stepBackoff{backoff: backoff}),
+		retry.WithRetriable(retriable),
+	}, opts...)...)
+
+	err := retrier.Do(context.Background(), func(context.Context) error {
+		return fn()
+	})
+
+	var exhaustedErr *retry.ExhaustedError
+	if errors.As(err, &exhaustedErr) {
+		if idleDelay > 0 {
+			// wait.ExponentialBackoff sleeps before it notices that the cap was exceeded.
+			time.Sleep(wait.Jitter(idleDelay, backoff.Jitter))
+		}
+		return exhaustedErr.Err
+	}
+	return err
+}
+
+// RetryOnConflict retries fn as long as it returns a conflict error. See OnError for details.
+func RetryOnConflict(backoff wait.Backoff, fn func() error, opts ...retry.Option) error {
+	return OnError(backoff, apierrors.IsConflict, fn, opts...)
+}
+
+// maxAttempts returns how often wait.ExponentialBackoff evaluates its condition for the given backoff. Exceeding
+// the cap ends the backoff without any further attempt, but only after sleeping for the current step once more.
+// This step is returned without jitter as idleDelay, which is zero if the steps are used up first.
+func maxAttempts(backoff wait.Backoff) (attempts int, idleDelay time.Duration) {
+	if backoff.Steps <= 0 {
+		return 0, 0
+	}
+	if backoff.Factor == 0 || backoff.Cap <= 0 {
+		return backoff.Steps, 0
+	}
+
+	steps := backoff.Steps
+	backoff.Jitter = 0
+	for attempt := 1; attempt < steps; attempt++ {
+		delay := backoff.Step()
+		if backoff.Steps == 0 {
+			return attempt, delay
+		}
+		if backoff.Factor <= 1 {
+			// The duration does not grow anymore and thus never exceeds the cap.
+			return steps, 0
+		}
+	}
+
+	return steps, 0
+}
+
+// stepBackoff produces the delays of a wait.Backoff in order. It must only be used for a single retry loop.
+type stepBackoff struct {
+	backoff wait.Backoff
+}
+
+// Delay returns the next step of the wrapped backoff.
+func (s *stepBackoff) Delay(int) time.Duration {
+	return s.backoff.Step()
+}
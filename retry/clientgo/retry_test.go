@@ -0,0 +1,168 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoretry "k8s.io/client-go/util/retry"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+var conflictErr = &apierrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonConflict}}
+
+func Test_OnError(t *testing.T) {
+	t.Run("should attempt as often as client-go", func(t *testing.T) {
+		backoffs := []wait.Backoff{
+			{Steps: 0, Duration: time.Microsecond, Factor: 2},
+			{Steps: 1, Duration: time.Microsecond, Factor: 2},
+			{Steps: 5, Duration: time.Microsecond, Factor: 1, Jitter: 0.1},
+			{Steps: 4, Duration: time.Microsecond, Factor: 5, Jitter: 0.1},
+			{Steps: 9999, Duration: time.Microsecond, Factor: 2, Cap: 100 * time.Microsecond},
+			{Steps: 9999, Duration: time.Millisecond, Factor: 0.5, Cap: 100 * time.Microsecond},
+			{Steps: 10, Duration: time.Microsecond, Factor: 0.5, Cap: 100 * time.Microsecond},
+		}
+		for _, backoff := range backoffs {
+			expectedCalls := 0
+			expectedErr := clientgoretry.OnError(backoff, retry.AlwaysRetryFunc, func() error {
+				expectedCalls++
+				return assert.AnError
+			})
+
+			actualCalls := 0
+			actualErr := OnError(backoff, retry.AlwaysRetryFunc, func() error {
+				actualCalls++
+				return assert.AnError
+			})
+
+			assert.Equal(t, expectedCalls, actualCalls, "backoff %+v", backoff)
+			assert.Equal(t, expectedErr, actualErr, "backoff %+v", backoff)
+		}
+	})
+	t.Run("should wait like client-go once the cap is exceeded", func(t *testing.T) {
+		// given
+		backoff := wait.Backoff{Steps: 9999, Duration: 20 * time.Millisecond, Factor: 2, Cap: 30 * time.Millisecond}
+		calls := 0
+		start := time.Now()
+
+		// when
+		err := OnError(backoff, retry.AlwaysRetryFunc, func() error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+	t.Run("should return non-retriable error", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		err := OnError(DefaultRetry, apierrors.IsConflict, fn)
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should apply additional options", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		fn := func() error {
+			return assert.AnError
+		}
+
+		// when
+		err := OnError(wait.Backoff{Steps: 2, Duration: time.Microsecond}, retry.AlwaysRetryFunc, fn, retry.WithTimeline(buf))
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Contains(t, buf.String(), `"attempt": 2`)
+	})
+}
+
+func Test_RetryOnConflict(t *testing.T) {
+	t.Run("should retry once and succeed", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			if calls == 1 {
+				return conflictErr
+			}
+			return nil
+		}
+
+		// when
+		err := RetryOnConflict(DefaultBackoff, fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("should return last conflict after all steps", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			return conflictErr
+		}
+
+		// when
+		err := RetryOnConflict(DefaultRetry, fn)
+
+		// then
+		assert.Same(t, conflictErr, err)
+		assert.Equal(t, 5, calls)
+	})
+}
+
+func Test_kubernetesPresets(t *testing.T) {
+	tests := []struct {
+		preset  retry.PresetName
+		backoff wait.Backoff
+	}{
+		{retry.PresetKubernetesConflict, clientgoretry.DefaultRetry},
+		{retry.PresetKubernetesBackoff, clientgoretry.DefaultBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			// given
+			sut := retry.New(retry.Preset(tt.preset), retry.WithRetriable(apierrors.IsConflict))
+			unjittered := tt.backoff
+			unjittered.Jitter = 0
+			calls := 0
+
+			// when
+			err := sut.Do(context.Background(), func(context.Context) error {
+				calls++
+				return conflictErr
+			})
+
+			// then
+			require.Error(t, err)
+			assert.Equal(t, tt.backoff.Steps, calls)
+			for attempt := 1; attempt < tt.backoff.Steps; attempt++ {
+				minimum := unjittered.Step()
+				for range 100 {
+					actual := sut.DelayFor(attempt)
+					assert.GreaterOrEqual(t, actual, minimum)
+					assert.LessOrEqual(t, actual, time.Duration(float64(minimum)*(1+tt.backoff.Jitter)))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the time to a Retrier. Tests may pass a fake clock with WithClock to drive backoff delays and
+// limits without actually waiting.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer firing once after the given duration.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event created by a Clock.
+type Timer interface {
+	// C returns the channel receiving the time once the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing and returns false if it already fired or was stopped.
+	Stop() bool
+}
+
+// WithClock sets the Clock used for backoff delays and limits. The watchdog configured with WithWatchdog always
+// uses the real time.
+func WithClock(clock Clock) Option {
+	return func(p *policy) {
+		p.clock = clock
+	}
+}
+
+// now returns the current time of the configured clock.
+func (p *policy) now() time.Time {
+	if p.clock == nil {
+		return time.Now()
+	}
+	return p.clock.Now()
+}
+
+// since returns the time passed since start according to the configured clock.
+func (p *policy) since(start time.Time) time.Duration {
+	if p.clock == nil {
+		return elapsedSince(start)
+	}
+	return p.clock.Now().Sub(start)
+}
+
+// sleep waits for the given delay on the configured clock and returns false if ctx is done before. Receiving from
+// wake ends the wait early.
+func (p *policy) sleep(ctx context.Context, delay time.Duration, wake <-chan struct{}) bool {
+	if p.clock == nil {
+		return sleep(ctx, delay, wake)
+	}
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := p.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-wake:
+		return true
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock fires every timer immediately and advances its time accordingly.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	timer := &fakeTimer{c: make(chan time.Time, 1)}
+	timer.c <- c.now
+	return timer
+}
+
+type fakeTimer struct {
+	c chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return false
+}
+
+// waitingClock creates timers that never fire and reports their creation, so that tests know that a loop waits.
+type waitingClock struct {
+	timers chan time.Duration
+}
+
+func newWaitingClock() *waitingClock {
+	return &waitingClock{timers: make(chan time.Duration, 10)}
+}
+
+func (c *waitingClock) Now() time.Time {
+	return time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+}
+
+func (c *waitingClock) NewTimer(d time.Duration) Timer {
+	c.timers <- d
+	return &fakeTimer{c: make(chan time.Time)}
+}
+
+func Test_WithClock(t *testing.T) {
+	t.Run("should wait on clock", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Hour)), WithLimit(0), WithMaxTries(3))
+		start := time.Now()
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Len(t, report.Attempts, 3)
+		assert.Equal(t, 2*time.Hour, report.Duration)
+		assert.Equal(t, time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC), report.Attempts[1].Start)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+	t.Run("should apply limit on clock", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithLimit(5*time.Minute))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, 6, exhaustedErr.Attempts)
+	})
+}
+
+func Test_policy_sleep(t *testing.T) {
+	t.Run("should return false on done context", func(t *testing.T) {
+		// given
+		sut := defaultPolicy()
+		WithClock(&fakeClock{})(&sut)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		ok := sut.sleep(ctx, 0, nil)
+
+		// then
+		assert.False(t, ok)
+	})
+}
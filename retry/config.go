@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Duration is a time.Duration encoded in JSON as a string like "1m30s".
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string like "1m30s".
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// PolicyConfig describes a policy in a configuration file. Fields that are not set keep the value of the base
+// policy. A Preset is applied before the other fields, which override single aspects of it.
+type PolicyConfig struct {
+	Preset        *PresetName `json:"preset,omitempty"`
+	MaxTries      *int        `json:"maxTries,omitempty"`
+	Limit         *Duration   `json:"limit,omitempty"`
+	InitialDelay  *Duration   `json:"initialDelay,omitempty"`
+	BackoffFactor *float64    `json:"backoffFactor,omitempty"`
+	MaxDelay      *Duration   `json:"maxDelay,omitempty"`
+	JitterPercent *float64    `json:"jitterPercent,omitempty"`
+}
+
+// Options returns the options corresponding to the set fields.
+func (c PolicyConfig) Options() []Option {
+	var opts []Option
+	if c.Preset != nil {
+		opts = append(opts, Preset(*c.Preset))
+	}
+	if c.MaxTries != nil {
+		opts = append(opts, WithMaxTries(*c.MaxTries))
+	}
+	if c.Limit != nil {
+		opts = append(opts, WithLimit(time.Duration(*c.Limit)))
+	}
+	if c.InitialDelay != nil {
+		opts = append(opts, WithInitialDelay(time.Duration(*c.InitialDelay)))
+	}
+	if c.BackoffFactor != nil {
+		opts = append(opts, WithBackoffFactor(*c.BackoffFactor))
+	}
+	if c.MaxDelay != nil {
+		opts = append(opts, WithMaxDelay(time.Duration(*c.MaxDelay)))
+	}
+	if c.JitterPercent != nil {
+		opts = append(opts, WithJitterPercent(*c.JitterPercent))
+	}
+	return opts
+}
+
+// LoadFile reads a JSON file mapping policy names to PolicyConfigs and applies it to the registry, e.g.
+//
+//	{"backup": {"maxTries": 5, "initialDelay": "2s", "limit": "10m"}}
+func LoadFile(path string, registry *Registry) error {
+	_, err := loadFile(path, registry, nil)
+	return err
+}
+
+// WatchFile applies the policies of the given file like LoadFile and reloads them whenever the file's content
+// changes until ctx is done. It polls the file with the given interval, which also works for Kubernetes ConfigMaps
+// mounted as volume as they are updated by swapping symlinks. An invalid file is logged and keeps the previous
+// policies in place. Only the initial load returns an error.
+func WatchFile(ctx context.Context, path string, registry *Registry, interval time.Duration) error {
+	content, err := loadFile(path, registry, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			reloaded, err := loadFile(path, registry, content)
+			if err != nil {
+				slog.Warn("failed to reload retry policies", "path", path, "error", err)
+				continue
+			}
+			content = reloaded
+		}
+	}()
+	return nil
+}
+
+// loadFile applies the file to the registry unless its content equals previous. It returns the file's content.
+func loadFile(path string, registry *Registry, previous []byte) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retry policies: %w", err)
+	}
+	if previous != nil && bytes.Equal(content, previous) {
+		return content, nil
+	}
+
+	var configs map[string]PolicyConfig
+	if err := json.Unmarshal(content, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse retry policies from %s: %w", path, err)
+	}
+	registry.Apply(configs)
+	return content, nil
+}
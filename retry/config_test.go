@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PolicyConfig_Options(t *testing.T) {
+	// given
+	var sut PolicyConfig
+	err := json.Unmarshal([]byte(`{"maxTries": 4, "limit": "1m", "initialDelay": "100ms", "backoffFactor": 3, "maxDelay": "1s", "jitterPercent": 0}`), &sut)
+	require.NoError(t, err)
+	p := defaultPolicy()
+
+	// when
+	for _, opt := range sut.Options() {
+		opt(&p)
+	}
+
+	// then
+	assert.Equal(t, 4, p.maxTries)
+	assert.Equal(t, time.Minute, p.limit)
+	assert.Equal(t, Exponential{Initial: 100 * time.Millisecond, Factor: 3, Max: time.Second}, p.backoff)
+}
+
+func Test_PolicyConfig_Options_preset(t *testing.T) {
+	// given
+	var sut PolicyConfig
+	err := json.Unmarshal([]byte(`{"preset": "conservative", "maxTries": 2}`), &sut)
+	require.NoError(t, err)
+	p := defaultPolicy()
+
+	// when
+	for _, opt := range sut.Options() {
+		opt(&p)
+	}
+
+	// then
+	assert.Equal(t, 2, p.maxTries)
+	assert.Equal(t, 5*time.Minute, p.limit)
+	assert.Equal(t, Exponential{Initial: time.Second, Factor: 2, Max: 30 * time.Second}, p.backoff)
+}
+
+func Test_Duration_JSON(t *testing.T) {
+	// given
+	var sut Duration
+
+	// when
+	err := json.Unmarshal([]byte(`"1m30s"`), &sut)
+	invalidErr := json.Unmarshal([]byte(`90`), &sut)
+	encoded, encodeErr := json.Marshal(sut)
+
+	// then
+	require.NoError(t, err)
+	assert.Error(t, invalidErr)
+	require.NoError(t, encodeErr)
+	assert.Equal(t, `"1m30s"`, string(encoded))
+}
+
+func Test_LoadFile(t *testing.T) {
+	t.Run("should apply policies", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "policies.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"backup": {"maxTries": 5}}`), 0o600))
+		registry := NewRegistry()
+
+		// when
+		err := LoadFile(path, registry)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 5, registry.Get("backup").policy.Load().maxTries)
+	})
+	t.Run("should fail on invalid file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "policies.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"backup": {"limit": 5}}`), 0o600))
+
+		// when
+		err := LoadFile(path, NewRegistry())
+
+		// then
+		assert.ErrorContains(t, err, "failed to parse retry policies")
+	})
+	t.Run("should fail on missing file", func(t *testing.T) {
+		// when
+		err := LoadFile(filepath.Join(t.TempDir(), "missing.json"), NewRegistry())
+
+		// then
+		assert.ErrorContains(t, err, "failed to read retry policies")
+	})
+}
+
+func Test_WatchFile(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "policies.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"backup": {"maxTries": 5}}`), 0o600))
+	registry := NewRegistry()
+	retrier := registry.Get("backup")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, WatchFile(ctx, path, registry, time.Millisecond))
+
+	// when
+	require.NoError(t, os.WriteFile(path, []byte(`invalid`), 0o600))
+	time.Sleep(10 * time.Millisecond)
+	keptMaxTries := retrier.policy.Load().maxTries
+	require.NoError(t, os.WriteFile(path, []byte(`{"backup": {"maxTries": 9}}`), 0o600))
+
+	// then
+	assert.Equal(t, 5, keptMaxTries)
+	assert.Eventually(t, func() bool {
+		return retrier.policy.Load().maxTries == 9
+	}, time.Second, time.Millisecond)
+}
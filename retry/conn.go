@@ -0,0 +1,171 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrConnManagerClosed is returned by ConnManager.Get after the manager was closed.
+var ErrConnManagerClosed = errors.New("connection manager is closed")
+
+// ConnConfig configures how a ConnManager handles its connections.
+type ConnConfig[T any] struct {
+	// Connect establishes a new connection. It is retried according to the Retrier of the ConnManager.
+	Connect func(ctx context.Context) (T, error)
+	// Healthy checks a connection before it is handed out and returns an error if it is broken. It is optional and
+	// should be cheap as it is called on every ConnManager.Get.
+	Healthy func(ctx context.Context, conn T) error
+	// Close releases a broken or no longer needed connection. It is optional.
+	Close func(conn T) error
+}
+
+// ConnManager owns a single connection, e.g. to a message broker or a database. It hands out the live connection
+// and transparently reconnects with backoff once the connection turns out to be broken. Connections are compared to
+// tell whether an invalidated one is still current, so T is usually a pointer or an interface backed by one. A
+// ConnManager is safe for concurrent use.
+type ConnManager[T comparable] struct {
+	retrier *Retrier
+	config  ConnConfig[T]
+
+	// mu guards closed and cancelConnect, which Close accesses without waiting for a running reconnect.
+	mu            sync.Mutex
+	closed        bool
+	cancelConnect context.CancelCauseFunc
+
+	// sem guards the fields below. It is a channel instead of a mutex so that waiting callers honour their context.
+	sem       chan struct{}
+	conn      T
+	connected bool
+}
+
+// NewConnManager creates a ConnManager connecting with the given Retrier. No connection is established until the
+// first call of Get.
+func NewConnManager[T comparable](retrier *Retrier, config ConnConfig[T]) *ConnManager[T] {
+	return &ConnManager[T]{
+		retrier: retrier,
+		config:  config,
+		sem:     make(chan struct{}, 1),
+	}
+}
+
+// Get returns the live connection. If there is none or the health check fails, Get reconnects with retries. The
+// error of the retry loop is returned if no connection could be established.
+func (m *ConnManager[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+	if !m.lock(ctx) {
+		return zero, ctx.Err()
+	}
+	defer m.unlock()
+
+	if m.isClosed() {
+		return zero, ErrConnManagerClosed
+	}
+	if m.connected {
+		if m.config.Healthy == nil || m.config.Healthy(ctx, m.conn) == nil {
+			return m.conn, nil
+		}
+		_ = m.drop()
+	}
+
+	connectCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if !m.startConnect(cancel) {
+		return zero, ErrConnManagerClosed
+	}
+	defer m.startConnect(nil)
+
+	var conn T
+	err := m.retrier.Do(connectCtx, func(ctx context.Context) error {
+		var err error
+		conn, err = m.config.Connect(ctx)
+		return err
+	})
+	if err != nil && errors.Is(context.Cause(connectCtx), ErrConnManagerClosed) {
+		return zero, ErrConnManagerClosed
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	m.conn = conn
+	m.connected = true
+	return conn, nil
+}
+
+// Invalidate drops broken, e.g. after a call on it failed, so that the next Get reconnects. If broken is no longer
+// the current connection because another caller already reconnected, the current one is kept. Invalidate waits for
+// a running reconnect and returns ctx.Err() if ctx is done before, or the error of ConnConfig.Close otherwise.
+func (m *ConnManager[T]) Invalidate(ctx context.Context, broken T) error {
+	if !m.lock(ctx) {
+		return ctx.Err()
+	}
+	defer m.unlock()
+
+	if !m.connected || m.conn != broken {
+		return nil
+	}
+	return m.drop()
+}
+
+// Close drops the current connection and makes all further calls of Get fail with ErrConnManagerClosed. A running
+// reconnect is canceled, so Close only waits until ConnConfig.Connect honours the cancellation of its context.
+func (m *ConnManager[T]) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	if m.cancelConnect != nil {
+		m.cancelConnect(ErrConnManagerClosed)
+	}
+	m.mu.Unlock()
+
+	m.sem <- struct{}{}
+	defer m.unlock()
+
+	return m.drop()
+}
+
+func (m *ConnManager[T]) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.closed
+}
+
+// startConnect registers cancel to abort the running reconnect on Close, or unregisters it if cancel is nil. It
+// returns false if the manager is closed already.
+func (m *ConnManager[T]) startConnect(cancel context.CancelCauseFunc) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cancelConnect = cancel
+	return !m.closed
+}
+
+func (m *ConnManager[T]) lock(ctx context.Context) bool {
+	select {
+	case m.sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (m *ConnManager[T]) unlock() {
+	<-m.sem
+}
+
+// drop closes the current connection, if any. It must only be called while holding the lock.
+func (m *ConnManager[T]) drop() error {
+	if !m.connected {
+		return nil
+	}
+
+	conn := m.conn
+	var zero T
+	m.conn = zero
+	m.connected = false
+	if m.config.Close == nil {
+		return nil
+	}
+	return m.config.Close(conn)
+}
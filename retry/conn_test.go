@@ -0,0 +1,204 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	id     int
+	broken bool
+	closed bool
+}
+
+func newFakeConnManager(failures int) (*ConnManager[*fakeConn], *[]*fakeConn) {
+	var conns []*fakeConn
+	calls := 0
+	sut := NewConnManager(New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(5)), ConnConfig[*fakeConn]{
+		Connect: func(ctx context.Context) (*fakeConn, error) {
+			calls++
+			if calls <= failures {
+				return nil, assert.AnError
+			}
+			conn := &fakeConn{id: len(conns) + 1}
+			conns = append(conns, conn)
+			return conn, nil
+		},
+		Healthy: func(ctx context.Context, conn *fakeConn) error {
+			if conn.broken {
+				return errors.New("broken")
+			}
+			return nil
+		},
+		Close: func(conn *fakeConn) error {
+			conn.closed = true
+			return nil
+		},
+	})
+	return sut, &conns
+}
+
+func Test_ConnManager_Get(t *testing.T) {
+	t.Run("should connect with retries", func(t *testing.T) {
+		// given
+		sut, conns := newFakeConnManager(2)
+
+		// when
+		conn, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, conn.id)
+		assert.Len(t, *conns, 1)
+	})
+	t.Run("should reuse healthy connection", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+
+		// when
+		second, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+	})
+	t.Run("should reconnect broken connection", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		first.broken = true
+
+		// when
+		second, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, second.id)
+		assert.True(t, first.closed)
+	})
+	t.Run("should fail if connecting is exhausted", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(10)
+
+		// when
+		_, err := sut.Get(context.Background())
+
+		// then
+		var exhaustedErr *ExhaustedError
+		assert.ErrorAs(t, err, &exhaustedErr)
+	})
+	t.Run("should honour context while waiting for lock", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		sut.sem <- struct{}{}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		_, err := sut.Get(ctx)
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func Test_ConnManager_Invalidate(t *testing.T) {
+	t.Run("should drop broken connection", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+
+		// when
+		err = sut.Invalidate(context.Background(), first)
+		second, getErr := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, getErr)
+		assert.True(t, first.closed)
+		assert.NotSame(t, first, second)
+	})
+	t.Run("should keep connection reconnected in the meantime", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		first.broken = true
+		second, err := sut.Get(context.Background())
+		require.NoError(t, err)
+
+		// when
+		err = sut.Invalidate(context.Background(), first)
+		third, getErr := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, getErr)
+		assert.False(t, second.closed)
+		assert.Same(t, second, third)
+	})
+	t.Run("should honour context while reconnect is running", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		sut.sem <- struct{}{}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := sut.Invalidate(ctx, &fakeConn{})
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func Test_ConnManager_Close(t *testing.T) {
+	t.Run("should close connection and fail further calls", func(t *testing.T) {
+		// given
+		sut, _ := newFakeConnManager(0)
+		conn, err := sut.Get(context.Background())
+		require.NoError(t, err)
+
+		// when
+		err = sut.Close()
+		_, getErr := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, conn.closed)
+		assert.ErrorIs(t, getErr, ErrConnManagerClosed)
+	})
+	t.Run("should cancel running reconnect", func(t *testing.T) {
+		// given
+		connecting := make(chan struct{})
+		sut := NewConnManager(New(WithBackoff(Constant(time.Hour))), ConnConfig[*fakeConn]{
+			Connect: func(ctx context.Context) (*fakeConn, error) {
+				close(connecting)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		})
+		getErr := make(chan error, 1)
+		go func() {
+			_, err := sut.Get(context.Background())
+			getErr <- err
+		}()
+		<-connecting
+
+		// when
+		err := sut.Close()
+
+		// then
+		require.NoError(t, err)
+		assert.ErrorIs(t, <-getErr, ErrConnManagerClosed)
+	})
+}
@@ -0,0 +1,26 @@
+package controllerruntime
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Apply applies obj with server-side apply as fieldOwner and retries on conflicts with other field managers. Such
+// conflicts often resolve by themselves, e.g. once a controller that briefly took over a field has finished.
+// Following the recommended strategy, Apply takes over the conflicting fields with force=true once forceAfter
+// attempts have failed with a conflict. A forceAfter of zero or less never forces. The defaults of the retry loop are
+// those of Update, opts are applied afterward and may change them.
+func Apply(ctx context.Context, c client.Client, obj client.Object, fieldOwner string, forceAfter int, opts ...retry.Option) error {
+	attempt := 0
+	return onConflict(ctx, opts, func(ctx context.Context) error {
+		attempt++
+		patchOpts := []client.PatchOption{client.FieldOwner(fieldOwner)}
+		if forceAfter > 0 && attempt > forceAfter {
+			patchOpts = append(patchOpts, client.ForceOwnership)
+		}
+		return c.Patch(ctx, obj, client.Apply, patchOpts...)
+	})
+}
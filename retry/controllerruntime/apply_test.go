@@ -0,0 +1,78 @@
+package controllerruntime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// recordingApplies returns a client answering apply patches with the given errors and recording their options.
+func recordingApplies(errs ...error) (client.Client, *[]*client.PatchOptions) {
+	var applied []*client.PatchOptions
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patchOpts := &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			applied = append(applied, patchOpts)
+			if len(errs) == 0 {
+				return nil
+			}
+			err := errs[0]
+			errs = errs[1:]
+			return err
+		},
+	}).Build()
+	return c, &applied
+}
+
+func Test_Apply(t *testing.T) {
+	t.Run("should escalate to force after conflicts", func(t *testing.T) {
+		// given
+		c, applied := recordingApplies(conflict(), conflict())
+
+		// when
+		err := Apply(context.Background(), c, newConfigMap(), "k8s-dogu-operator", 2)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, *applied, 3)
+		for _, opts := range *applied {
+			assert.Equal(t, "k8s-dogu-operator", opts.FieldManager)
+		}
+		assert.Nil(t, (*applied)[0].Force)
+		assert.Nil(t, (*applied)[1].Force)
+		require.NotNil(t, (*applied)[2].Force)
+		assert.True(t, *(*applied)[2].Force)
+	})
+	t.Run("should never force without threshold", func(t *testing.T) {
+		// given
+		c, applied := recordingApplies(conflict(), conflict(), conflict(), conflict(), conflict())
+
+		// when
+		err := Apply(context.Background(), c, newConfigMap(), "k8s-dogu-operator", 0)
+
+		// then
+		require.Error(t, err)
+		require.Len(t, *applied, 5)
+		for _, opts := range *applied {
+			assert.Nil(t, opts.Force)
+		}
+	})
+	t.Run("should not retry other errors", func(t *testing.T) {
+		// given
+		c, applied := recordingApplies(assert.AnError)
+
+		// when
+		err := Apply(context.Background(), c, &corev1.ConfigMap{}, "k8s-dogu-operator", 1)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Len(t, *applied, 1)
+	})
+}
@@ -0,0 +1,93 @@
+package controllerruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+const (
+	// ConditionRetrying is true while a retry loop started with DoWithConditions waits for its next attempt.
+	ConditionRetrying = "Retrying"
+	// ConditionDegraded is true once a retry loop started with DoWithConditions failed for good.
+	ConditionDegraded = "Degraded"
+
+	// ReasonAttemptFailed is the reason of the Retrying condition.
+	ReasonAttemptFailed = "AttemptFailed"
+	// ReasonRetriesExhausted is the reason of the Degraded condition if the retry loop was exhausted.
+	ReasonRetriesExhausted = "RetriesExhausted"
+	// ReasonFailed is the reason of the Degraded condition if the workload failed with a non-retriable error.
+	ReasonFailed = "Failed"
+)
+
+// DoWithConditions executes fn with r and reflects the state of the retry loop in the status of the object with the
+// given key. conditions returns the conditions of the object's status, usually the field Status.Conditions.
+//
+// Before every retry, the condition Retrying is set with the number and the error of the failed attempt and the time
+// of the next one. If fn succeeds, the conditions Retrying and Degraded are removed. Otherwise, Retrying is removed
+// and Degraded is set with the final error. The status is changed with PatchStatus, so the updates are retried on
+// conflicts as well. Failing to update the status while retrying is logged with the logger of ctx and does not
+// interrupt the loop, failing to update it at the end is returned together with the error of fn, if any.
+//
+// opts are passed to r.Do. DoWithConditions relies on retry.WithOnRetry, so a hook set that way is replaced.
+func DoWithConditions[T client.Object](ctx context.Context, r retry.Interface, c client.Client, key client.ObjectKey, conditions func(T) *[]metav1.Condition, fn func(ctx context.Context) error, opts ...retry.Option) error {
+	setConditions := func(ctx context.Context, mutate func(conditions *[]metav1.Condition, generation int64)) error {
+		return PatchStatus(ctx, c, key, func(obj T) {
+			mutate(conditions(obj), obj.GetGeneration())
+		})
+	}
+
+	onRetry := retry.WithOnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+		next := time.Now().Add(delay).UTC().Format(time.RFC3339)
+		statusErr := setConditions(ctx, func(conditions *[]metav1.Condition, generation int64) {
+			meta.SetStatusCondition(conditions, metav1.Condition{
+				Type:               ConditionRetrying,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: generation,
+				Reason:             ReasonAttemptFailed,
+				Message:            fmt.Sprintf("attempt %d failed, next attempt at %s: %v", attempt, next, err),
+			})
+		})
+		if statusErr != nil {
+			log.FromContext(ctx).Error(statusErr, "failed to set retry condition", "object", key)
+		}
+	})
+
+	err := r.Do(ctx, fn, append(opts[:len(opts):len(opts)], onRetry)...)
+	if ctx.Err() != nil {
+		return err
+	}
+
+	statusErr := setConditions(ctx, func(conditions *[]metav1.Condition, generation int64) {
+		meta.RemoveStatusCondition(conditions, ConditionRetrying)
+		if err == nil {
+			meta.RemoveStatusCondition(conditions, ConditionDegraded)
+			return
+		}
+
+		reason := ReasonFailed
+		var exhaustedErr *retry.ExhaustedError
+		if errors.As(err, &exhaustedErr) {
+			reason = ReasonRetriesExhausted
+		}
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             reason,
+			Message:            err.Error(),
+		})
+	})
+	if statusErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to update retry conditions of %s: %w", key, statusErr))
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package controllerruntime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+var pdbKey = client.ObjectKey{Namespace: "ecosystem", Name: "ldap"}
+
+func pdbConditions(pdb *policyv1.PodDisruptionBudget) *[]metav1.Condition {
+	return &pdb.Status.Conditions
+}
+
+func newConditionClient(conditions ...metav1.Condition) client.Client {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: pdbKey.Namespace, Name: pdbKey.Name, Generation: 3},
+		Status:     policyv1.PodDisruptionBudgetStatus{Conditions: conditions},
+	}
+	return fake.NewClientBuilder().WithObjects(pdb).WithStatusSubresource(pdb).Build()
+}
+
+func getConditions(t *testing.T, c client.Client) []metav1.Condition {
+	t.Helper()
+	pdb := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, c.Get(context.Background(), pdbKey, pdb))
+	return pdb.Status.Conditions
+}
+
+func Test_DoWithConditions(t *testing.T) {
+	t.Run("should set retrying condition while retrying and clear it on success", func(t *testing.T) {
+		// given
+		c := newConditionClient(metav1.Condition{Type: ConditionDegraded, Status: metav1.ConditionTrue, Reason: ReasonFailed})
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(3))
+		attempts := 0
+		var retrying *metav1.Condition
+
+		// when
+		err := DoWithConditions(context.Background(), sut, c, pdbKey, pdbConditions, func(ctx context.Context) error {
+			attempts++
+			if attempts == 2 {
+				retrying = meta.FindStatusCondition(getConditions(t, c), ConditionRetrying)
+				return nil
+			}
+			return assert.AnError
+		})
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, retrying)
+		assert.Equal(t, metav1.ConditionTrue, retrying.Status)
+		assert.Equal(t, ReasonAttemptFailed, retrying.Reason)
+		assert.Equal(t, int64(3), retrying.ObservedGeneration)
+		assert.Contains(t, retrying.Message, "attempt 1 failed, next attempt at ")
+		assert.Contains(t, retrying.Message, assert.AnError.Error())
+		assert.Empty(t, getConditions(t, c))
+	})
+	t.Run("should set degraded condition on exhaustion", func(t *testing.T) {
+		// given
+		c := newConditionClient()
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(2))
+
+		// when
+		err := DoWithConditions(context.Background(), sut, c, pdbKey, pdbConditions, func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		conditions := getConditions(t, c)
+		require.Len(t, conditions, 1)
+		assert.Equal(t, ConditionDegraded, conditions[0].Type)
+		assert.Equal(t, ReasonRetriesExhausted, conditions[0].Reason)
+		assert.Equal(t, err.Error(), conditions[0].Message)
+	})
+	t.Run("should set degraded condition on non-retriable error", func(t *testing.T) {
+		// given
+		c := newConditionClient()
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)))
+
+		// when
+		err := DoWithConditions(context.Background(), sut, c, pdbKey, pdbConditions, func(ctx context.Context) error {
+			return retry.Abort(assert.AnError)
+		})
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		degraded := meta.FindStatusCondition(getConditions(t, c), ConditionDegraded)
+		require.NotNil(t, degraded)
+		assert.Equal(t, ReasonFailed, degraded.Reason)
+	})
+	t.Run("should return error updating the status", func(t *testing.T) {
+		// given
+		c := fake.NewClientBuilder().Build()
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)))
+
+		// when
+		err := DoWithConditions(context.Background(), sut, c, pdbKey, pdbConditions, func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update retry conditions of ecosystem/ldap")
+	})
+}
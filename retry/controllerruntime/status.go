@@ -0,0 +1,41 @@
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// UpdateStatus works like Update but updates the status subresource of the object via Status().Update().
+func UpdateStatus[T client.Object](ctx context.Context, c client.Client, key client.ObjectKey, mutate func(T), opts ...retry.Option) error {
+	return onConflict(ctx, opts, func(ctx context.Context) error {
+		obj := newObject[T]()
+		err := c.Get(ctx, key, obj)
+		if err != nil {
+			return retry.Abort(fmt.Errorf("failed to get object %s: %w", key, err))
+		}
+
+		mutate(obj)
+		return c.Status().Update(ctx, obj)
+	})
+}
+
+// PatchStatus works like UpdateStatus but only sends the changes made by mutate as merge patch via
+// Status().Patch(). The patch contains the resource version of the fetched object, so that it fails with a conflict
+// instead of overwriting concurrent changes and is retried then.
+func PatchStatus[T client.Object](ctx context.Context, c client.Client, key client.ObjectKey, mutate func(T), opts ...retry.Option) error {
+	return onConflict(ctx, opts, func(ctx context.Context) error {
+		obj := newObject[T]()
+		err := c.Get(ctx, key, obj)
+		if err != nil {
+			return retry.Abort(fmt.Errorf("failed to get object %s: %w", key, err))
+		}
+
+		patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		mutate(obj)
+		return c.Status().Patch(ctx, obj, patch)
+	})
+}
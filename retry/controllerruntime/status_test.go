@@ -0,0 +1,103 @@
+package controllerruntime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var podKey = client.ObjectKey{Namespace: "ecosystem", Name: "ldap-0"}
+
+func newPod() *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: podKey.Namespace, Name: podKey.Name}}
+}
+
+func newStatusClient(funcs interceptor.Funcs) client.Client {
+	return fake.NewClientBuilder().
+		WithObjects(newPod()).
+		WithStatusSubresource(&corev1.Pod{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func markReady(pod *corev1.Pod) {
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.Message = pod.Status.Message + "ready"
+}
+
+func Test_UpdateStatus(t *testing.T) {
+	// given
+	failures := 2
+	c := newStatusClient(interceptor.Funcs{
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if failures > 0 {
+				failures--
+				return conflict()
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	})
+
+	// when
+	err := UpdateStatus(context.Background(), c, podKey, markReady)
+
+	// then
+	require.NoError(t, err)
+	actual := &corev1.Pod{}
+	require.NoError(t, c.Get(context.Background(), podKey, actual))
+	assert.Equal(t, corev1.PodRunning, actual.Status.Phase)
+	assert.Equal(t, "ready", actual.Status.Message)
+}
+
+func Test_PatchStatus(t *testing.T) {
+	t.Run("should retry patch on conflict", func(t *testing.T) {
+		// given
+		failures := 1
+		c := newStatusClient(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				if failures > 0 {
+					failures--
+					return conflict()
+				}
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		// when
+		err := PatchStatus(context.Background(), c, podKey, markReady)
+
+		// then
+		require.NoError(t, err)
+		actual := &corev1.Pod{}
+		require.NoError(t, c.Get(context.Background(), podKey, actual))
+		assert.Equal(t, "ready", actual.Status.Message)
+	})
+	t.Run("should send resource version with patch", func(t *testing.T) {
+		// given
+		var patches []string
+		c := newStatusClient(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				data, err := patch.Data(obj)
+				require.NoError(t, err)
+				patches = append(patches, string(data))
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		// when
+		err := PatchStatus(context.Background(), c, podKey, markReady)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, patches, 1)
+		assert.Contains(t, patches[0], `"resourceVersion"`)
+		assert.Contains(t, patches[0], `"message":"ready"`)
+	})
+}
@@ -0,0 +1,46 @@
+// Package controllerruntime provides conflict-retrying update helpers for the client of
+// sigs.k8s.io/controller-runtime. It is kept apart from the package retry/k8s so that services using plain client-go
+// do not depend on controller-runtime.
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Update fetches the object with the given key, applies mutate to it and updates it. If the update fails with a
+// conflict because the object was modified meanwhile, the whole sequence is retried with a freshly fetched object,
+// so mutate must be idempotent. By default, Update retries like retry.PresetKubernetesConflict, i.e. tries five
+// times with a delay of 10 milliseconds plus up to 10% jitter. opts are applied afterward and may change this.
+func Update[T client.Object](ctx context.Context, c client.Client, key client.ObjectKey, mutate func(T), opts ...retry.Option) error {
+	return onConflict(ctx, opts, func(ctx context.Context) error {
+		obj := newObject[T]()
+		err := c.Get(ctx, key, obj)
+		if err != nil {
+			return retry.Abort(fmt.Errorf("failed to get object %s: %w", key, err))
+		}
+
+		mutate(obj)
+		return c.Update(ctx, obj)
+	})
+}
+
+// onConflict executes fn until it does not fail with a conflict. Conflicts are usually resolved by the next attempt,
+// so they are retried quickly but only a few times by default, like DefaultRetry of client-go does.
+func onConflict(ctx context.Context, opts []retry.Option, fn func(ctx context.Context) error) error {
+	options := append([]retry.Option{retry.Preset(retry.PresetKubernetesConflict)}, opts...)
+	options = append(options, retry.WithRetriable(apierrors.IsConflict))
+	return retry.New(options...).Do(ctx, fn)
+}
+
+// newObject returns a new, empty object of the type T points to.
+func newObject[T client.Object]() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
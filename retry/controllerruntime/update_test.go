@@ -0,0 +1,106 @@
+package controllerruntime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+var configMapKey = client.ObjectKey{Namespace: "ecosystem", Name: "config"}
+
+func newConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: configMapKey.Namespace, Name: configMapKey.Name},
+		Data:       map[string]string{"count": ""},
+	}
+}
+
+func conflict() error {
+	return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, configMapKey.Name, assert.AnError)
+}
+
+// failingUpdates returns interceptor functions failing the given number of updates with err.
+func failingUpdates(failures int, err error) interceptor.Funcs {
+	return interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if failures > 0 {
+				failures--
+				return err
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+}
+
+func Test_Update(t *testing.T) {
+	t.Run("should retry whole sequence on conflict", func(t *testing.T) {
+		// given
+		c := fake.NewClientBuilder().WithObjects(newConfigMap()).WithInterceptorFuncs(failingUpdates(2, conflict())).Build()
+		mutations := 0
+
+		// when
+		err := Update(context.Background(), c, configMapKey, func(cm *corev1.ConfigMap) {
+			mutations++
+			cm.Data["count"] += "x"
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, mutations)
+		actual := &corev1.ConfigMap{}
+		require.NoError(t, c.Get(context.Background(), configMapKey, actual))
+		assert.Equal(t, "x", actual.Data["count"])
+	})
+	t.Run("should not retry other errors", func(t *testing.T) {
+		// given
+		c := fake.NewClientBuilder().WithObjects(newConfigMap()).WithInterceptorFuncs(failingUpdates(1, assert.AnError)).Build()
+		mutations := 0
+
+		// when
+		err := Update(context.Background(), c, configMapKey, func(cm *corev1.ConfigMap) {
+			mutations++
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, mutations)
+	})
+	t.Run("should fail if object does not exist", func(t *testing.T) {
+		// given
+		c := fake.NewClientBuilder().Build()
+
+		// when
+		err := Update(context.Background(), c, configMapKey, func(cm *corev1.ConfigMap) {})
+
+		// then
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.ErrorContains(t, err, "failed to get object ecosystem/config")
+	})
+	t.Run("should give up after configured attempts", func(t *testing.T) {
+		// given
+		c := fake.NewClientBuilder().WithObjects(newConfigMap()).WithInterceptorFuncs(failingUpdates(10, conflict())).Build()
+		mutations := 0
+
+		// when
+		err := Update(context.Background(), c, configMapKey, func(cm *corev1.ConfigMap) {
+			mutations++
+		}, retry.WithMaxTries(2))
+
+		// then
+		var exhaustedErr *retry.ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, 2, mutations)
+	})
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CooldownError is returned without executing the workload while a Retrier cools down after an exhausted retry loop.
+type CooldownError struct {
+	// Until is the time the cooldown ends.
+	Until time.Time
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("retries are cooling down after an exhausted retry loop until %s", e.Until.Format(time.RFC3339))
+}
+
+// WithCooldown lets the Retrier cool down for the given duration once a retry loop ended with an ExhaustedError.
+// Meanwhile, all calls fail fast with a CooldownError instead of retrying a dependency that is most likely still
+// down. Afterward, the Retrier retries again as usual. This is a lightweight alternative to a circuit breaker that
+// needs no probing. A duration of zero or less disables the cooldown.
+func WithCooldown(cooldown time.Duration) Option {
+	return func(p *policy) {
+		p.cooldown = cooldown
+	}
+}
+
+// cooldown tracks the end of the current cooldown of a Retrier.
+type cooldown struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// observe starts a cooldown if a retry loop executed with p was exhausted.
+func (c *cooldown) observe(p *policy, outcome Outcome) {
+	if p.cooldown <= 0 || outcome != OutcomeExhausted {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.until = p.now().Add(p.cooldown)
+}
+
+// check returns a CooldownError if a retry loop executed with p must not start yet.
+func (c *cooldown) check(p *policy) error {
+	if p.cooldown <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p.now().Before(c.until) {
+		return &CooldownError{Until: c.until}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithCooldown(t *testing.T) {
+	t.Run("should fail fast during cooldown", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithCooldown(time.Hour))
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+		called := false
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		// then
+		var cooldownErr *CooldownError
+		require.True(t, errors.As(err, &cooldownErr))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), cooldownErr.Until, time.Minute)
+		assert.False(t, called)
+		assert.Equal(t, OutcomeCoolingDown, report.Outcome)
+	})
+	t.Run("should retry again after cooldown", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithCooldown(10*time.Millisecond))
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+		time.Sleep(20 * time.Millisecond)
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should not cool down after non-retriable error", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithCooldown(time.Hour))
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		})
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,54 @@
+package retry
+
+import "sync"
+
+// WithHealthThreshold marks the Retrier unhealthy once the given number of consecutive retry loops ended because
+// their limits were reached, i.e. with an ExhaustedError. The next successful loop marks it healthy again. onChange
+// is called with the new state on every transition and may be nil if the state is only polled with
+// Retrier.Healthy, e.g. by a readiness probe. A threshold of zero or less disables the health reporting.
+func WithHealthThreshold(consecutive int, onChange func(healthy bool)) Option {
+	return func(p *policy) {
+		p.healthThreshold = consecutive
+		p.onHealthChange = onChange
+	}
+}
+
+// health tracks the consecutive exhausted retry loops of a Retrier.
+type health struct {
+	mu        sync.Mutex
+	exhausted int
+	unhealthy bool
+}
+
+// Healthy reports whether the Retrier was not marked unhealthy by the threshold of WithHealthThreshold. A Retrier
+// without threshold is always healthy.
+func (r *Retrier) Healthy() bool {
+	r.health.mu.Lock()
+	defer r.health.mu.Unlock()
+	return !r.health.unhealthy
+}
+
+// observe updates the health according to the outcome of a retry loop executed with p.
+func (h *health) observe(p *policy, outcome Outcome) {
+	if p.healthThreshold <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	wasUnhealthy := h.unhealthy
+	switch outcome {
+	case OutcomeSucceeded:
+		h.exhausted = 0
+		h.unhealthy = false
+	case OutcomeExhausted:
+		h.exhausted++
+		h.unhealthy = h.exhausted >= p.healthThreshold
+	}
+	changed := wasUnhealthy != h.unhealthy
+	healthy := !h.unhealthy
+	h.mu.Unlock()
+
+	if changed && p.onHealthChange != nil {
+		p.onHealthChange(healthy)
+	}
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Retrier_Healthy(t *testing.T) {
+	exhaust := func(ctx context.Context) error {
+		return assert.AnError
+	}
+	succeed := func(ctx context.Context) error {
+		return nil
+	}
+
+	t.Run("should be healthy without threshold", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1))
+
+		// when
+		for range 5 {
+			_ = sut.Do(context.Background(), exhaust)
+		}
+
+		// then
+		assert.True(t, sut.Healthy())
+	})
+	t.Run("should become unhealthy after consecutive exhaustions and recover", func(t *testing.T) {
+		// given
+		var changes []bool
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithHealthThreshold(3, func(healthy bool) {
+			changes = append(changes, healthy)
+		}))
+
+		// when
+		_ = sut.Do(context.Background(), exhaust)
+		_ = sut.Do(context.Background(), exhaust)
+		healthyBeforeThreshold := sut.Healthy()
+		_ = sut.Do(context.Background(), exhaust)
+		healthyAtThreshold := sut.Healthy()
+		_ = sut.Do(context.Background(), exhaust)
+		_ = sut.Do(context.Background(), succeed)
+
+		// then
+		assert.True(t, healthyBeforeThreshold)
+		assert.False(t, healthyAtThreshold)
+		assert.True(t, sut.Healthy())
+		assert.Equal(t, []bool{false, true}, changes)
+	})
+	t.Run("should reset count on success", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1), WithHealthThreshold(2, nil))
+
+		// when
+		_ = sut.Do(context.Background(), exhaust)
+		_ = sut.Do(context.Background(), succeed)
+		_ = sut.Do(context.Background(), exhaust)
+
+		// then
+		assert.True(t, sut.Healthy())
+	})
+	t.Run("should ignore non-retriable errors", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1), WithHealthThreshold(1, nil))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, sut.Healthy())
+	})
+}
@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// WithCleanup sets a hook invoked with the context and the error of every failed attempt, before the Retrier waits
+// for the next one. It releases resources the attempt left behind, e.g. temporary files or half-open connections,
+// that would otherwise leak across attempts. The hook is also invoked after the last attempt if it failed.
+func WithCleanup(cleanup func(ctx context.Context, err error)) Option {
+	return func(p *policy) {
+		p.cleanup = cleanup
+	}
+}
+
+// WithBeforeAttempt sets a hook invoked before every attempt with its context and number, starting at 1. It prepares
+// the attempt, e.g. by refreshing credentials or resolving the endpoint again, and may hand the results to the
+// workload with the AttemptValues of ctx. If the hook returns an error, the workload is not executed and the error
+// is handled as the error of the attempt.
+func WithBeforeAttempt(beforeAttempt func(ctx context.Context, attempt int) error) Option {
+	return func(p *policy) {
+		p.beforeAttempt = beforeAttempt
+	}
+}
+
+// WithOnRetry sets a hook invoked whenever a failed attempt is going to be retried, with the number and the error of
+// the attempt and the delay before the next one. It is not invoked for the last attempt of a loop. This lets callers
+// publish the state of a running loop, e.g. in the status of a custom resource.
+func WithOnRetry(onRetry func(ctx context.Context, attempt int, err error, delay time.Duration)) Option {
+	return func(p *policy) {
+		p.onRetry = onRetry
+	}
+}
+
+// WithOnRecovered sets a hook invoked when a workload eventually succeeds after at least one failed attempt, with
+// the number of attempts and the error of the first one. Such recoveries hide flaky dependencies from callers, so
+// counting them reveals flakiness trends before they turn into outages.
+func WithOnRecovered(onRecovered func(attempts int, firstErr error)) Option {
+	return func(p *policy) {
+		p.onRecovered = onRecovered
+	}
+}
+
+// recovered invokes the hook set with WithOnRecovered if the workload failed before.
+func (p *policy) recovered(attempts int, firstErr error) {
+	if p.onRecovered != nil && firstErr != nil {
+		p.onRecovered(attempts, firstErr)
+	}
+}
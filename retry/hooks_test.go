@@ -0,0 +1,236 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithCleanup(t *testing.T) {
+	t.Run("should clean up after every failed attempt", func(t *testing.T) {
+		// given
+		var cleaned []error
+		var events []string
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithCleanup(func(ctx context.Context, err error) {
+			cleaned = append(cleaned, err)
+			events = append(events, "cleanup")
+		}))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			events = append(events, "attempt")
+			if attempts < 3 {
+				return fmt.Errorf("attempt %d: %w", attempts, assert.AnError)
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, cleaned, 2)
+		assert.EqualError(t, cleaned[0], "attempt 1: "+assert.AnError.Error())
+		assert.Equal(t, []string{"attempt", "cleanup", "attempt", "cleanup", "attempt"}, events)
+	})
+	t.Run("should clean up after last attempt", func(t *testing.T) {
+		// given
+		cleanups := 0
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithCleanup(func(ctx context.Context, err error) {
+			cleanups++
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, 2, cleanups)
+	})
+	t.Run("should clean up in daemon loops", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		cleanups := 0
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithCleanup(func(ctx context.Context, err error) {
+			cleanups++
+		}))
+		runs := 0
+
+		// when
+		err := RunLoop(ctx, sut, time.Millisecond, func(ctx context.Context) error {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+			return assert.AnError
+		})
+
+		// then
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, 3, cleanups)
+	})
+}
+
+func Test_WithBeforeAttempt(t *testing.T) {
+	t.Run("should prepare every attempt", func(t *testing.T) {
+		// given
+		var prepared []int
+		token := ""
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithBeforeAttempt(func(ctx context.Context, attempt int) error {
+			prepared = append(prepared, attempt)
+			token = fmt.Sprintf("token-%d", attempt)
+			return nil
+		}))
+		var tokens []string
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			tokens = append(tokens, token)
+			if len(tokens) < 3 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, prepared)
+		assert.Equal(t, []string{"token-1", "token-2", "token-3"}, tokens)
+	})
+	t.Run("should handle error of hook as error of attempt", func(t *testing.T) {
+		// given
+		var cleaned []error
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3),
+			WithBeforeAttempt(func(ctx context.Context, attempt int) error {
+				if attempt == 1 {
+					return assert.AnError
+				}
+				return nil
+			}),
+			WithCleanup(func(ctx context.Context, err error) {
+				cleaned = append(cleaned, err)
+			}))
+		executions := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			executions++
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, executions)
+		assert.Equal(t, []error{assert.AnError}, cleaned)
+	})
+	t.Run("should not execute workload if hook fails with non-retriable error", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithBeforeAttempt(func(ctx context.Context, attempt int) error {
+			return Abort(assert.AnError)
+		}))
+		executions := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			executions++
+			return nil
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, executions)
+	})
+}
+
+func Test_WithOnRetry(t *testing.T) {
+	t.Run("should notify about retried attempts", func(t *testing.T) {
+		// given
+		var retried []int
+		var delays []time.Duration
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(3), WithOnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			assert.ErrorIs(t, err, assert.AnError)
+			retried = append(retried, attempt)
+			delays = append(delays, delay)
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, []int{1, 2}, retried)
+		assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, delays)
+	})
+}
+
+func Test_WithOnRecovered(t *testing.T) {
+	t.Run("should notify about success after failures", func(t *testing.T) {
+		// given
+		firstErr := errors.New("first")
+		var recoveredAttempts int
+		var recoveredErr error
+		sut := New(WithBackoff(Constant(0)), WithOnRecovered(func(attempts int, err error) {
+			recoveredAttempts = attempts
+			recoveredErr = err
+		}))
+		errs := []error{firstErr, assert.AnError}
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			if len(errs) == 0 {
+				return nil
+			}
+			next := errs[0]
+			errs = errs[1:]
+			return next
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, recoveredAttempts)
+		assert.Same(t, firstErr, recoveredErr)
+	})
+	t.Run("should not notify about immediate success or failure", func(t *testing.T) {
+		// given
+		notified := false
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithOnRecovered(func(int, error) {
+			notified = true
+		}))
+
+		// when
+		succeededErr := sut.Do(context.Background(), func(ctx context.Context) error { return nil })
+		failedErr := sut.Do(context.Background(), func(ctx context.Context) error { return assert.AnError })
+
+		// then
+		assert.NoError(t, succeededErr)
+		assert.Error(t, failedErr)
+		assert.False(t, notified)
+	})
+	t.Run("should notify if outcome check confirms ambiguous attempt", func(t *testing.T) {
+		// given
+		var recoveredAttempts int
+		sut := New(WithBackoff(Constant(0)), WithAtMostOnce(func(context.Context) (bool, error) {
+			return true, nil
+		}), WithOnRecovered(func(attempts int, _ error) {
+			recoveredAttempts = attempts
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return OutcomeUnknown(assert.AnError)
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, recoveredAttempts)
+	})
+}
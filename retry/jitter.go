@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// WithJitterPercent varies every delay of the configured backoff randomly by up to ±percent percent. This spreads
+// retries of many clients without having to pick a dedicated jitter algorithm. Values are clamped to [0, 100].
+func WithJitterPercent(percent float64) Option {
+	return func(p *policy) {
+		p.jitterPercent = min(max(percent, 0), 100)
+		p.fullJitter = false
+	}
+}
+
+// WithFullJitter picks every delay randomly between zero and the delay of the configured backoff, which spreads
+// retries the most while never exceeding the backoff. It replaces the jitter set with WithJitterPercent and vice
+// versa, whichever option comes last wins.
+func WithFullJitter() Option {
+	return func(p *policy) {
+		p.fullJitter = true
+		p.jitterPercent = 0
+	}
+}
+
+// WithMonotonicDelays guarantees that a delay never falls below the delay after the previous attempt, even if the
+// jitter would shorten it. Some upstreams interpret shrinking intervals as abusive clients.
+func WithMonotonicDelays() Option {
+	return func(p *policy) {
+		p.monotonic = true
+	}
+}
+
+// WithJitterIdentity derives the jitter set with WithJitterPercent deterministically from an identity like a pod or
+// node name instead of choosing it randomly. Fleets of identical operators thereby spread their retries without any
+// coordination, while every single instance behaves reproducibly. The jitter still differs from attempt to attempt.
+func WithJitterIdentity(identity string) Option {
+	return func(p *policy) {
+		p.jitterIdentity = identity
+	}
+}
+
+// identityRandom returns a number in [0, 1) derived from the identity and the attempt.
+func identityRandom(identity string, attempt int) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(identity))
+	_, _ = h.Write([]byte{byte(attempt), byte(attempt >> 8), byte(attempt >> 16), byte(attempt >> 24)})
+	// A fresh generator seeded with the hash mixes the bits of similar identities like "pod-1" and "pod-2". It is
+	// used without rand.Rand, which would be allocated for every delay, and yields the same numbers as its Float64.
+	return float64(rand.NewPCG(h.Sum64(), 0).Uint64()<<11>>11) / (1 << 53)
+}
+
+// applyJitter varies delay randomly by up to ±percent percent. It draws from the global generator of math/rand/v2,
+// whose state is kept per thread of the runtime without a mutex, so that thousands of concurrent retry loops do not
+// contend for it.
+func applyJitter(delay time.Duration, percent float64) time.Duration {
+	return scaleJitter(delay, percent, rand.Float64())
+}
+
+// fullJitter scales delay by random, a number in [0, 1).
+func fullJitter(delay time.Duration, random float64) time.Duration {
+	return time.Duration(random * float64(delay))
+}
+
+// scaleJitter varies delay by up to ±percent percent according to random, a number in [0, 1).
+func scaleJitter(delay time.Duration, percent float64, random float64) time.Duration {
+	if percent <= 0 || delay <= 0 {
+		return delay
+	}
+
+	factor := 1 + (random*2-1)*percent/100
+	jittered := float64(delay) * factor
+	if jittered >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(jittered)
+}
@@ -0,0 +1,177 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_applyJitter(t *testing.T) {
+	t.Run("should stay within bounds", func(t *testing.T) {
+		for range 1000 {
+			actual := applyJitter(time.Second, 20)
+			assert.GreaterOrEqual(t, actual, 800*time.Millisecond)
+			assert.LessOrEqual(t, actual, 1200*time.Millisecond)
+		}
+	})
+	t.Run("should vary delay", func(t *testing.T) {
+		seen := map[time.Duration]bool{}
+		for range 100 {
+			seen[applyJitter(time.Second, 50)] = true
+		}
+		assert.Greater(t, len(seen), 1)
+	})
+	t.Run("should keep delay without jitter", func(t *testing.T) {
+		assert.Equal(t, time.Second, applyJitter(time.Second, 0))
+		assert.Equal(t, time.Duration(0), applyJitter(0, 50))
+	})
+	t.Run("should not overflow", func(t *testing.T) {
+		actual := applyJitter(math.MaxInt64, 100)
+		assert.GreaterOrEqual(t, actual, time.Duration(0))
+	})
+}
+
+func Test_WithJitterPercent(t *testing.T) {
+	t.Run("should clamp percentage", func(t *testing.T) {
+		p := defaultPolicy()
+		WithJitterPercent(150)(&p)
+		assert.Equal(t, float64(100), p.jitterPercent)
+		WithJitterPercent(-5)(&p)
+		assert.Equal(t, float64(0), p.jitterPercent)
+	})
+	t.Run("should jitter delay of any backoff", func(t *testing.T) {
+		p := defaultPolicy()
+		WithBackoff(Constant(time.Second))(&p)
+		WithJitterPercent(10)(&p)
+		for attempt := 1; attempt < 100; attempt++ {
+			actual := p.delay(attempt)
+			assert.GreaterOrEqual(t, actual, 900*time.Millisecond)
+			assert.LessOrEqual(t, actual, 1100*time.Millisecond)
+		}
+	})
+}
+
+func Test_WithFullJitter(t *testing.T) {
+	t.Run("should pick delays between zero and backoff", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Second)), WithFullJitter())
+		seen := map[time.Duration]bool{}
+
+		// when
+		for range 1000 {
+			actual := sut.DelayFor(1)
+			seen[actual] = true
+
+			// then
+			assert.GreaterOrEqual(t, actual, time.Duration(0))
+			assert.Less(t, actual, time.Second)
+		}
+		assert.Greater(t, len(seen), 1)
+	})
+	t.Run("should be replaced by jitter percent and vice versa", func(t *testing.T) {
+		p := defaultPolicy()
+		WithJitterPercent(20)(&p)
+		WithFullJitter()(&p)
+		assert.True(t, p.fullJitter)
+		assert.Equal(t, 0.0, p.jitterPercent)
+
+		WithJitterPercent(20)(&p)
+		assert.False(t, p.fullJitter)
+		assert.Equal(t, 20.0, p.jitterPercent)
+	})
+	t.Run("should derive full jitter from identity", func(t *testing.T) {
+		first := New(WithBackoff(Constant(time.Second)), WithFullJitter(), WithJitterIdentity("pod-1"))
+		second := New(WithBackoff(Constant(time.Second)), WithFullJitter(), WithJitterIdentity("pod-1"))
+		assert.Equal(t, first.DelayFor(3), second.DelayFor(3))
+	})
+}
+
+func Test_WithMonotonicDelays(t *testing.T) {
+	// given
+	sut := New(WithBackoff(Constant(200*time.Microsecond)), WithJitterPercent(50), WithMonotonicDelays(),
+		WithMaxTries(30))
+
+	// when
+	report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	// then
+	require.Error(t, err)
+	require.Len(t, report.Attempts, 30)
+	for i := 1; i < len(report.Attempts)-1; i++ {
+		assert.GreaterOrEqual(t, report.Attempts[i].Delay, report.Attempts[i-1].Delay)
+	}
+}
+
+func Test_WithJitterIdentity(t *testing.T) {
+	t.Run("should be deterministic per identity", func(t *testing.T) {
+		// given
+		first := New(WithBackoff(Constant(time.Second)), WithJitterPercent(50), WithJitterIdentity("pod-1"))
+		again := New(WithBackoff(Constant(time.Second)), WithJitterPercent(50), WithJitterIdentity("pod-1"))
+
+		// then
+		for attempt := 1; attempt <= 10; attempt++ {
+			assert.Equal(t, first.DelayFor(attempt), again.DelayFor(attempt))
+		}
+	})
+	t.Run("should differ between identities and attempts", func(t *testing.T) {
+		// given
+		seen := map[time.Duration]bool{}
+
+		// when
+		for i := 0; i < 10; i++ {
+			sut := New(WithBackoff(Constant(time.Second)), WithJitterPercent(50), WithJitterIdentity(fmt.Sprintf("pod-%d", i)))
+			seen[sut.DelayFor(1)] = true
+			seen[sut.DelayFor(2)] = true
+		}
+
+		// then
+		assert.Greater(t, len(seen), 15)
+	})
+	t.Run("should stay within bounds", func(t *testing.T) {
+		for attempt := 1; attempt <= 1000; attempt++ {
+			delay := scaleJitter(time.Second, 20, identityRandom("node", attempt))
+			assert.GreaterOrEqual(t, delay, 800*time.Millisecond)
+			assert.LessOrEqual(t, delay, 1200*time.Millisecond)
+		}
+	})
+}
+
+func Test_identityRandom(t *testing.T) {
+	t.Run("should match generator of math/rand", func(t *testing.T) {
+		// given
+		h := fnv.New64a()
+		_, _ = h.Write([]byte("pod-1"))
+		_, _ = h.Write([]byte{3, 0, 0, 0})
+		expected := rand.New(rand.NewPCG(h.Sum64(), 0)).Float64()
+
+		// when
+		actual := identityRandom("pod-1", 3)
+
+		// then
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should not allocate", func(t *testing.T) {
+		actual := testing.AllocsPerRun(100, func() {
+			_ = identityRandom("pod-1", 3)
+		})
+		assert.Equal(t, 0.0, actual)
+	})
+}
+
+func Benchmark_applyJitter(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = applyJitter(time.Second, 20)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// NewClock adapts a clock of k8s.io/utils/clock to retry.Clock. Passing the FakeClock of k8s.io/utils/clock/testing
+// to retry.WithClock lets tests drive backoff delays and limits by stepping the fake clock.
+func NewClock(c clock.Clock) retry.Clock {
+	return clockAdapter{clock: c}
+}
+
+type clockAdapter struct {
+	clock clock.Clock
+}
+
+// Now returns the current time of the adapted clock.
+func (a clockAdapter) Now() time.Time {
+	return a.clock.Now()
+}
+
+// NewTimer creates a timer of the adapted clock.
+func (a clockAdapter) NewTimer(d time.Duration) retry.Timer {
+	return a.clock.NewTimer(d)
+}
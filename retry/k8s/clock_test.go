@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_NewClock(t *testing.T) {
+	// given
+	fakeClock := clocktesting.NewFakeClock(time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC))
+	sut := retry.New(retry.WithClock(NewClock(fakeClock)), retry.WithBackoff(retry.Constant(time.Hour)),
+		retry.WithLimit(0), retry.WithMaxTries(2))
+	result := make(chan *retry.Report, 1)
+
+	// when
+	go func() {
+		report, _ := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+		result <- report
+	}()
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	fakeClock.Step(time.Hour)
+
+	// then
+	report := <-result
+	assert.Len(t, report.Attempts, 2)
+	assert.Equal(t, time.Hour, report.Duration)
+}
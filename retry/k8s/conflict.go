@@ -0,0 +1,53 @@
+// Package k8s provides retry helpers for workloads talking to the Kubernetes API. It is kept apart from the core
+// retry package so that services without Kubernetes do not depend on the k8s.io modules.
+package k8s
+
+import (
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	clientgoretry "github.com/cloudogu/retry-lib/retry/clientgo"
+)
+
+var conflictBackoff = wait.Backoff{
+	Duration: 1500 * time.Millisecond,
+	Factor:   1.5,
+	Jitter:   0,
+	Steps:    9999,
+	Cap:      30 * time.Second,
+}
+
+// conflictMessage is contained in the message of the conflict the API server reports if an object was modified
+// since it was read.
+const conflictMessage = "the object has been modified; please apply your changes to the latest version and try again"
+
+// OnConflict provides a K8s-way "retrier" mechanism to avoid conflicts on resource updates. Conflicts are detected
+// with IsConflict, so they are also retried if the error was flattened to a string on its way.
+func OnConflict(fn func() error) error {
+	return clientgoretry.OnError(conflictBackoff, IsConflict, fn)
+}
+
+// OnConflictWithAttempts works like OnConflict and also returns how often fn was executed, e.g. to log that an
+// update succeeded after a number of conflicts.
+func OnConflictWithAttempts(fn func() error) (int, error) {
+	attempts := 0
+	err := OnConflict(func() error {
+		attempts++
+		return fn()
+	})
+	return attempts, err
+}
+
+// IsConflict reports whether err is a conflict of the API server. Besides errors of the type
+// apierrors.StatusError, it recognizes errors whose message contains the one of the API server for modified
+// objects. This keeps conflicts retriable even if intermediate layers lose the type, e.g. by wrapping the error
+// with fmt.Errorf and %v or by passing it through a process boundary.
+func IsConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	return apierrors.IsConflict(err) || strings.Contains(err.Error(), conflictMessage)
+}
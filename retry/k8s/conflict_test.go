@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_OnConflict(t *testing.T) {
+	t.Run("should retry once and succeed", func(t *testing.T) {
+		// given
+		retryCount := 0
+		fn := func() error {
+			retryCount++
+			if retryCount == 1 {
+				return &errors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonConflict}}
+			}
+			return nil
+		}
+
+		// when
+		err := OnConflict(fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, retryCount)
+	})
+	t.Run("should fail", func(t *testing.T) {
+		// given
+		fn := func() error {
+			println(fmt.Sprintf("Current time: %s", time.Now()))
+			return assert.AnError
+		}
+
+		// when
+		err := OnConflict(fn)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func Test_OnConflictWithAttempts(t *testing.T) {
+	t.Run("should count attempts until success", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			if calls == 1 {
+				return &errors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonConflict}}
+			}
+			return nil
+		}
+
+		// when
+		attempts, err := OnConflictWithAttempts(fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, calls, attempts)
+	})
+	t.Run("should count non-conflict failure", func(t *testing.T) {
+		// when
+		attempts, err := OnConflictWithAttempts(func() error { return assert.AnError })
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func Test_IsConflict(t *testing.T) {
+	conflictErr := errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "config", fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+
+	assert.True(t, IsConflict(conflictErr))
+	assert.True(t, IsConflict(fmt.Errorf("failed to update: %w", conflictErr)))
+	assert.True(t, IsConflict(fmt.Errorf("failed to update: %v", conflictErr)))
+	assert.True(t, IsConflict(stderrors.New(conflictErr.Error())))
+	assert.False(t, IsConflict(errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "config")))
+	assert.False(t, IsConflict(assert.AnError))
+	assert.False(t, IsConflict(nil))
+}
+
+func Test_OnConflict_flattenedError(t *testing.T) {
+	// given
+	calls := 0
+	fn := func() error {
+		calls++
+		if calls == 1 {
+			return stderrors.New(`Operation cannot be fulfilled on dogus.k8s.cloudogu.com "ldap": the object has been modified; please apply your changes to the latest version and try again`)
+		}
+		return nil
+	}
+
+	// when
+	err := OnConflict(fn)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
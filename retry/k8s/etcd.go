@@ -0,0 +1,30 @@
+package k8s
+
+import "strings"
+
+// etcdLeaderChangeMessages are contained in the errors the API server passes through from etcd while etcd elects a
+// new leader.
+var etcdLeaderChangeMessages = []string{
+	"etcdserver: leader changed",
+	"etcdserver: no leader",
+	"etcdserver: raft proposal dropped",
+	"etcdserver: request timed out, possibly due to previous leader failure",
+}
+
+// IsEtcdLeaderChange reports whether err was caused by a leader election of the etcd cluster backing the API server.
+// Such errors are transient, but the API server reports them as internal errors, so that they are neither
+// recognized as conflicts nor as server timeouts. The message is inspected as it is the only indication of the
+// cause. IsEtcdLeaderChange can be combined with IsConflict or passed to retry.OnError directly.
+func IsEtcdLeaderChange(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := err.Error()
+	for _, leaderChange := range etcdLeaderChangeMessages {
+		if strings.Contains(message, leaderChange) {
+			return true
+		}
+	}
+	return false
+}
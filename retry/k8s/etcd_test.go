@@ -0,0 +1,21 @@
+package k8s
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_IsEtcdLeaderChange(t *testing.T) {
+	assert.True(t, IsEtcdLeaderChange(errors.NewInternalError(stderrors.New("etcdserver: leader changed"))))
+	assert.True(t, IsEtcdLeaderChange(fmt.Errorf("failed to update dogu: %w", stderrors.New("rpc error: code = Unavailable desc = etcdserver: raft proposal dropped"))))
+	assert.True(t, IsEtcdLeaderChange(stderrors.New("etcdserver: no leader")))
+	assert.True(t, IsEtcdLeaderChange(stderrors.New("etcdserver: request timed out, possibly due to previous leader failure")))
+	assert.False(t, IsEtcdLeaderChange(errors.NewInternalError(assert.AnError)))
+	assert.False(t, IsEtcdLeaderChange(errors.NewConflict(schema.GroupResource{Resource: "dogus"}, "ldap", assert.AnError)))
+	assert.False(t, IsEtcdLeaderChange(nil))
+}
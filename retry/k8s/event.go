@@ -0,0 +1,57 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// ReasonRetriesExhausted is the reason of the events emitted by EventReporter.
+const ReasonRetriesExhausted = "RetriesExhausted"
+
+// maxEventMessageLength is the maximum length of event messages accepted by the API server.
+const maxEventMessageLength = 1024
+
+// EventReporter returns a retry.ErrorReporter emitting a Warning event on object once a retry loop for it is
+// exhausted, so that cluster admins see the failure with kubectl describe. As the involved object differs between
+// loops, the reporter is usually passed per call:
+//
+//	err := retrier.Do(ctx, fn, retry.WithErrorReporter(k8s.EventReporter(recorder, obj)))
+func EventReporter(recorder record.EventRecorder, object runtime.Object) retry.ErrorReporter {
+	return retry.ErrorReporterFunc(func(_ context.Context, operation string, err error) {
+		recorder.Event(object, corev1.EventTypeWarning, ReasonRetriesExhausted, eventMessage(operation, err))
+	})
+}
+
+func eventMessage(operation string, err error) string {
+	message := "retry loop exhausted"
+	if operation != "" {
+		message = fmt.Sprintf("retry loop %q exhausted", operation)
+	}
+	var exhaustedErr *retry.ExhaustedError
+	if errors.As(err, &exhaustedErr) {
+		message = fmt.Sprintf("%s after %d attempt(s): %v", message, exhaustedErr.Attempts, exhaustedErr.Err)
+	} else {
+		message = fmt.Sprintf("%s: %v", message, err)
+	}
+
+	if len(message) > maxEventMessageLength {
+		return truncate(message, maxEventMessageLength-3) + "..."
+	}
+	return message
+}
+
+// truncate cuts s to at most n bytes at a rune boundary, so that the result stays valid UTF-8.
+func truncate(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func TestEventReporter(t *testing.T) {
+	t.Run("should emit warning event on exhaustion", func(t *testing.T) {
+		// given
+		recorder := record.NewFakeRecorder(10)
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ldap-0", Namespace: "ecosystem"}}
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(2), retry.WithOperation("backup"))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		}, retry.WithErrorReporter(EventReporter(recorder, pod)))
+
+		// then
+		require.Error(t, err)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, `Warning RetriesExhausted retry loop "backup" exhausted after 2 attempt(s): `+assert.AnError.Error(), <-recorder.Events)
+	})
+	t.Run("should not emit event on success", func(t *testing.T) {
+		// given
+		recorder := record.NewFakeRecorder(10)
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(2))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		}, retry.WithErrorReporter(EventReporter(recorder, &corev1.Pod{})))
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func Test_eventMessage(t *testing.T) {
+	t.Run("should describe other errors", func(t *testing.T) {
+		assert.Equal(t, "retry loop exhausted: "+assert.AnError.Error(), eventMessage("", assert.AnError))
+	})
+	t.Run("should truncate long messages", func(t *testing.T) {
+		// when
+		actual := eventMessage("", &retry.ExhaustedError{Attempts: 1, Err: assert.AnError})
+		long := eventMessage("", &retry.ExhaustedError{Attempts: 1, Err: errors.New(strings.Repeat("x", 2000))})
+
+		// then
+		assert.Equal(t, "retry loop exhausted after 1 attempt(s): "+assert.AnError.Error(), actual)
+		assert.Len(t, long, maxEventMessageLength)
+		assert.True(t, strings.HasSuffix(long, "..."))
+	})
+	t.Run("should truncate at rune boundary", func(t *testing.T) {
+		// when
+		actual := eventMessage("", errors.New(strings.Repeat("ü", 1000)))
+
+		// then
+		assert.LessOrEqual(t, len(actual), maxEventMessageLength)
+		assert.True(t, utf8.ValidString(actual))
+		assert.True(t, strings.HasSuffix(actual, "ü..."))
+	})
+}
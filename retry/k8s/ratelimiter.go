@@ -0,0 +1,42 @@
+package k8s
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// RateLimiter is a workqueue.TypedRateLimiter computing the requeue delays of items with the policy of a Retrier, so
+// that a controller uses the same tuned policy for retries within a reconciliation and for the requeues of failed
+// ones. It is backed by a retry.RequeueTracker.
+type RateLimiter[T comparable] struct {
+	tracker *retry.RequeueTracker[T]
+}
+
+var _ workqueue.TypedRateLimiter[string] = (*RateLimiter[string])(nil)
+
+// NewRateLimiter creates a RateLimiter with the backoff and jitter of r, e.g. for the controller options of
+// controller-runtime:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//		WithOptions(controller.TypedOptions[reconcile.Request]{RateLimiter: k8s.NewRateLimiter[reconcile.Request](retrier)})
+func NewRateLimiter[T comparable](r *retry.Retrier) *RateLimiter[T] {
+	return &RateLimiter[T]{tracker: retry.NewRequeueTracker[T](r)}
+}
+
+// When records another failure of item and returns the delay before it is processed again.
+func (l *RateLimiter[T]) When(item T) time.Duration {
+	return l.tracker.Failure(item)
+}
+
+// Forget resets the failures of item.
+func (l *RateLimiter[T]) Forget(item T) {
+	l.tracker.Success(item)
+}
+
+// NumRequeues returns the number of consecutive failures of item.
+func (l *RateLimiter[T]) NumRequeues(item T) int {
+	return l.tracker.Failures(item)
+}
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("should compute delays with policy", func(t *testing.T) {
+		// given
+		sut := NewRateLimiter[string](retry.New(retry.WithInitialDelay(time.Second), retry.WithBackoffFactor(2)))
+
+		// when
+		delays := []time.Duration{sut.When("ldap"), sut.When("ldap"), sut.When("cas")}
+
+		// then
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, time.Second}, delays)
+		assert.Equal(t, 2, sut.NumRequeues("ldap"))
+	})
+	t.Run("should forget item", func(t *testing.T) {
+		// given
+		sut := NewRateLimiter[string](retry.New(retry.WithBackoff(retry.Constant(time.Second))))
+		sut.When("ldap")
+
+		// when
+		sut.Forget("ldap")
+
+		// then
+		assert.Equal(t, 0, sut.NumRequeues("ldap"))
+	})
+	t.Run("should drive workqueue", func(t *testing.T) {
+		// given
+		queue := workqueue.NewTypedRateLimitingQueue[string](NewRateLimiter[string](retry.New(retry.WithBackoff(retry.Constant(time.Millisecond)))))
+		defer queue.ShutDown()
+
+		// when
+		queue.AddRateLimited("ldap")
+		item, _ := queue.Get()
+
+		// then
+		assert.Equal(t, "ldap", item)
+		assert.Equal(t, 1, queue.NumRequeues("ldap"))
+	})
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// clientThrottlingMessage prefixes the errors of client-go if a request could not pass the client-side rate limiter,
+// usually because the request's context would expire before.
+const clientThrottlingMessage = "client rate limiter Wait returned an error"
+
+// IsClientThrottled reports whether err was caused by the client-side rate limiter of client-go, which is configured
+// with the QPS and Burst of rest.Config.
+func IsClientThrottled(err error) bool {
+	return err != nil && strings.Contains(err.Error(), clientThrottlingMessage)
+}
+
+// IsServerThrottled reports whether the API server rejected a request with 429 Too Many Requests, e.g. because the
+// API Priority and Fairness limits of the request's priority level were reached.
+func IsServerThrottled(err error) bool {
+	return apierrors.IsTooManyRequests(err)
+}
+
+// ThrottlingClassifier returns a retry.Classifier retrying throttled requests to the API server. If the API server
+// advertised when to retry, as it does for requests rejected by API Priority and Fairness and for server timeouts,
+// the next attempt is scheduled after that delay instead of the one of the backoff. Requests held back by the
+// client-side rate limiter are retried after the backoff; this only helps if the attempts have their own deadline,
+// e.g. set with retry.WithAttemptTimeout. All other errors are passed to fallback, which aborts the loop if nil.
+func ThrottlingClassifier(fallback retry.Classifier) retry.Classifier {
+	if fallback == nil {
+		fallback = retry.Predicate(func(error) bool { return false })
+	}
+
+	return retry.ClassifierFunc(func(err error) retry.Decision {
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+			return retry.RetryAfter(time.Duration(seconds) * time.Second)
+		}
+		if IsServerThrottled(err) || IsClientThrottled(err) {
+			return retry.DecisionRetry
+		}
+		return fallback.Classify(err)
+	})
+}
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func TestThrottlingClassifier(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	clientThrottled := fmt.Errorf("%s: rate: Wait(n=1) would exceed context deadline", clientThrottlingMessage)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected retry.Decision
+	}{
+		{name: "priority and fairness", err: apierrors.NewTooManyRequests("too many requests", 3), expected: retry.RetryAfter(3 * time.Second)},
+		{name: "wrapped priority and fairness", err: fmt.Errorf("list pods: %w", apierrors.NewTooManyRequests("too many requests", 1)), expected: retry.RetryAfter(time.Second)},
+		{name: "too many requests without delay", err: apierrors.NewTooManyRequests("too many requests", 0), expected: retry.DecisionRetry},
+		{name: "server timeout", err: apierrors.NewServerTimeout(pods, "list", 2), expected: retry.RetryAfter(2 * time.Second)},
+		{name: "client-side throttling", err: fmt.Errorf("get pod: %w", clientThrottled), expected: retry.DecisionRetry},
+		{name: "conflict", err: apierrors.NewConflict(pods, "ldap-0", assert.AnError), expected: retry.DecisionAbort},
+		{name: "other", err: assert.AnError, expected: retry.DecisionAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ThrottlingClassifier(nil).Classify(tt.err))
+		})
+	}
+
+	t.Run("should pass other errors to fallback", func(t *testing.T) {
+		// given
+		sut := ThrottlingClassifier(retry.Predicate(IsConflict))
+
+		// when
+		actual := sut.Classify(apierrors.NewConflict(pods, "ldap-0", assert.AnError))
+
+		// then
+		assert.Equal(t, retry.DecisionRetry, actual)
+	})
+}
@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// UpdateUnstructured fetches the object with the given name via the dynamic client, applies mutate to it and updates
+// it. This lets operators manage custom resources they have no typed clients for. If the update fails with a
+// conflict, the whole sequence is retried with a freshly fetched object, so mutate must be idempotent. An error of
+// mutate, e.g. of unstructured.SetNestedField, is returned without retrying. By default, UpdateUnstructured retries
+// like retry.PresetKubernetesConflict, i.e. tries five times with a delay of 10 milliseconds plus up to 10% jitter.
+// opts are applied afterward and may change this.
+func UpdateUnstructured(ctx context.Context, resource dynamic.ResourceInterface, name string, mutate func(obj *unstructured.Unstructured) error, opts ...retry.Option) (*unstructured.Unstructured, error) {
+	options := append([]retry.Option{retry.Preset(retry.PresetKubernetesConflict)}, opts...)
+	retrier := retry.New(append(options, retry.WithRetriable(IsConflict))...)
+
+	var updated *unstructured.Unstructured
+	err := retrier.Do(ctx, func(ctx context.Context) error {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return retry.Abort(fmt.Errorf("failed to get object %s: %w", name, err))
+		}
+
+		err = mutate(obj)
+		if err != nil {
+			return retry.Abort(fmt.Errorf("failed to mutate object %s: %w", name, err))
+		}
+
+		updated, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var doguResource = schema.GroupVersionResource{Group: "k8s.cloudogu.com", Version: "v2", Resource: "dogus"}
+
+func newDoguClient(t *testing.T, updateFailures int, updateErr error) (*dynamicfake.FakeDynamicClient, *int) {
+	t.Helper()
+	dogu := &unstructured.Unstructured{}
+	dogu.SetAPIVersion("k8s.cloudogu.com/v2")
+	dogu.SetKind("Dogu")
+	dogu.SetNamespace("ecosystem")
+	dogu.SetName("ldap")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{doguResource: "DoguList"}, dogu)
+
+	updates := 0
+	client.PrependReactor("update", "dogus", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updates++
+		if updates <= updateFailures {
+			return true, nil, updateErr
+		}
+		return false, nil, nil
+	})
+	return client, &updates
+}
+
+func Test_UpdateUnstructured(t *testing.T) {
+	conflictErr := apierrors.NewConflict(doguResource.GroupResource(), "ldap", assert.AnError)
+	setVersion := func(obj *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(obj.Object, "2.6.8-1", "spec", "version")
+	}
+
+	t.Run("should retry whole sequence on conflict", func(t *testing.T) {
+		// given
+		client, updates := newDoguClient(t, 2, conflictErr)
+		resource := client.Resource(doguResource).Namespace("ecosystem")
+
+		// when
+		updated, err := UpdateUnstructured(context.Background(), resource, "ldap", setVersion)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, *updates)
+		version, _, _ := unstructured.NestedString(updated.Object, "spec", "version")
+		assert.Equal(t, "2.6.8-1", version)
+		stored, err := resource.Get(context.Background(), "ldap", metav1.GetOptions{})
+		require.NoError(t, err)
+		version, _, _ = unstructured.NestedString(stored.Object, "spec", "version")
+		assert.Equal(t, "2.6.8-1", version)
+	})
+	t.Run("should not retry other errors", func(t *testing.T) {
+		// given
+		client, updates := newDoguClient(t, 1, assert.AnError)
+
+		// when
+		_, err := UpdateUnstructured(context.Background(), client.Resource(doguResource).Namespace("ecosystem"), "ldap", setVersion)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, *updates)
+	})
+	t.Run("should return error of mutate", func(t *testing.T) {
+		// given
+		client, updates := newDoguClient(t, 0, nil)
+
+		// when
+		_, err := UpdateUnstructured(context.Background(), client.Resource(doguResource).Namespace("ecosystem"), "ldap", func(obj *unstructured.Unstructured) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to mutate object ldap")
+		assert.Equal(t, 0, *updates)
+	})
+	t.Run("should fail if object does not exist", func(t *testing.T) {
+		// given
+		client, _ := newDoguClient(t, 0, nil)
+
+		// when
+		_, err := UpdateUnstructured(context.Background(), client.Resource(doguResource).Namespace("ecosystem"), "cas", setVersion)
+
+		// then
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,38 @@
+package retry
+
+import "sync"
+
+// Kick ends the backoff wait of all retry loops of the Retrier that are currently waiting, so that they start their
+// next attempt immediately. This is useful if a failure was likely fixed, e.g. by a configuration change. Loops
+// configured with WithTrigger do not wait for an event afterward either. Loops that are executing an attempt right
+// now are not affected.
+func (r *Retrier) Kick() {
+	r.kicks.kick()
+}
+
+// kicker broadcasts kicks to all waiting retry loops by closing a shared channel.
+type kicker struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel that is closed by the next kick.
+func (k *kicker) wait() <-chan struct{} {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.ch == nil {
+		k.ch = make(chan struct{})
+	}
+	return k.ch
+}
+
+func (k *kicker) kick() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.ch != nil {
+		close(k.ch)
+		k.ch = nil
+	}
+}
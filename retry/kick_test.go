@@ -0,0 +1,103 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retrier_Kick(t *testing.T) {
+	t.Run("should skip current wait", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Hour)), WithLimit(0))
+		calls := &atomic.Int32{}
+		result := make(chan error, 1)
+		go func() {
+			result <- sut.Do(context.Background(), func(ctx context.Context) error {
+				if calls.Add(1) == 1 {
+					return assert.AnError
+				}
+				return nil
+			})
+		}()
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+		// when
+		require.Eventually(t, func() bool {
+			sut.Kick()
+			return calls.Load() == 2
+		}, time.Second, 5*time.Millisecond)
+
+		// then
+		assert.NoError(t, <-result)
+	})
+	t.Run("should wake run loop", func(t *testing.T) {
+		// given
+		sut := New()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := &atomic.Int32{}
+		go func() {
+			_ = RunLoop(ctx, sut, time.Hour, func(ctx context.Context) error {
+				calls.Add(1)
+				return nil
+			})
+		}()
+
+		// when
+		kick := func() bool {
+			sut.Kick()
+			return calls.Load() >= 2
+		}
+
+		// then
+		assert.Eventually(t, kick, time.Second, 5*time.Millisecond)
+	})
+	t.Run("should wake run loop waiting for trigger", func(t *testing.T) {
+		// given
+		clock := newWaitingClock()
+		sut := New(WithTrigger(make(chan struct{})), WithClock(clock))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := &atomic.Int32{}
+		result := make(chan error, 1)
+		go func() {
+			result <- RunLoop(ctx, sut, time.Hour, func(ctx context.Context) error {
+				if calls.Add(1) == 2 {
+					cancel()
+				}
+				return nil
+			})
+		}()
+		<-clock.timers
+
+		// when
+		sut.Kick()
+
+		// then
+		select {
+		case err := <-result:
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, int32(2), calls.Load())
+		case <-time.After(time.Second):
+			t.Fatal("run loop waits for trigger after kick")
+		}
+	})
+	t.Run("should not affect later waits", func(t *testing.T) {
+		// given
+		sut := New()
+		sut.Kick()
+		start := time.Now()
+
+		// when
+		ok := sut.policy.Load().sleep(context.Background(), 10*time.Millisecond, sut.kicks.wait())
+
+		// then
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+}
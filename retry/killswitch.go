@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// DisabledEnvVar names the environment variable that disables all retries at startup if set to a true value like
+// "true" or "1".
+const DisabledEnvVar = "RETRY_LIB_DISABLED"
+
+var retriesDisabled atomic.Bool
+
+func init() {
+	disabled, _ := strconv.ParseBool(os.Getenv(DisabledEnvVar))
+	retriesDisabled.Store(disabled)
+}
+
+// DisabledError is returned instead of retrying while retries are disabled with SetRetriesDisabled or the
+// environment variable DisabledEnvVar.
+type DisabledError struct {
+	// Err contains the error of the single attempt.
+	Err error
+}
+
+// Error returns the error's string representation.
+func (e *DisabledError) Error() string {
+	return fmt.Sprintf("retries are disabled: %s", e.Err)
+}
+
+// Unwrap returns the error of the single attempt.
+func (e *DisabledError) Unwrap() error {
+	return e.Err
+}
+
+// SetRetriesDisabled is a kill switch for incident response when retries amplify an outage. While disabled, every
+// Retrier executes workloads only once and returns a DisabledError instead of retrying. Daemon loops of RunLoop
+// are not affected as stopping them would cause another outage.
+func SetRetriesDisabled(disabled bool) {
+	retriesDisabled.Store(disabled)
+}
+
+// RetriesDisabled returns whether retries are currently disabled.
+func RetriesDisabled() bool {
+	return retriesDisabled.Load()
+}
@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetRetriesDisabled(t *testing.T) {
+	t.Run("should execute workload only once", func(t *testing.T) {
+		// given
+		SetRetriesDisabled(true)
+		defer SetRetriesDisabled(false)
+		sut := New(WithBackoff(Constant(time.Millisecond)))
+		calls := 0
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		var disabledErr *DisabledError
+		require.ErrorAs(t, err, &disabledErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, OutcomeDisabled, report.Outcome)
+	})
+	t.Run("should not affect successful and non-retriable workloads", func(t *testing.T) {
+		// given
+		SetRetriesDisabled(true)
+		defer SetRetriesDisabled(false)
+		sut := New(WithRetriable(func(err error) bool { return false }))
+
+		// when
+		succeeded := sut.Do(context.Background(), func(ctx context.Context) error { return nil })
+		failed := sut.Do(context.Background(), func(ctx context.Context) error { return assert.AnError })
+
+		// then
+		assert.NoError(t, succeeded)
+		assert.Same(t, assert.AnError, failed)
+	})
+	t.Run("should retry again after enabling", func(t *testing.T) {
+		// given
+		SetRetriesDisabled(true)
+		SetRetriesDisabled(false)
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(2))
+		calls := 0
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.False(t, RetriesDisabled())
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs every failed attempt that is going to be retried with the given logger. Use WithLogSampling to
+// reduce the number of messages of long retry loops.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *policy) {
+		p.logger = logger
+	}
+}
+
+// WithLogSampling only logs the first and then every Nth failed attempt of a retry loop, which prevents log floods
+// while still surfacing that the loop is retrying. Each sampled message contains the number of attempts suppressed
+// since the previous one. Values below 2 log every attempt.
+func WithLogSampling(everyN int) Option {
+	return func(p *policy) {
+		p.logEveryN = everyN
+	}
+}
+
+// logAttempt logs a failed attempt that is retried after delay, honouring the sampling.
+func (p *policy) logAttempt(ctx context.Context, attempt int, err error, delay time.Duration) {
+	if p.logger == nil {
+		return
+	}
+
+	suppressed := 0
+	if p.logEveryN > 1 {
+		if (attempt-1)%p.logEveryN != 0 {
+			return
+		}
+		if attempt > 1 {
+			suppressed = p.logEveryN - 1
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+	}
+	if p.operation != "" {
+		attrs = append(attrs, slog.String("operation", p.operation))
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, slog.Int("suppressed", suppressed))
+	}
+	p.logger.LogAttrs(ctx, slog.LevelWarn, "attempt failed, retrying", attrs...)
+}
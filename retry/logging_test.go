@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger() (*slog.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(handler), buf
+}
+
+func Test_WithLogger(t *testing.T) {
+	// given
+	logger, buf := newTestLogger()
+	sut := New(WithLogger(logger), WithOperation("backup"), WithBackoff(Constant(time.Millisecond)), WithMaxTries(3))
+
+	// when
+	_ = sut.Do(context.Background(), func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	// then
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, `level=WARN msg="attempt failed, retrying" attempt=1 delay=1ms error="assert.AnError general error for testing" operation=backup`, lines[0])
+}
+
+func Test_WithLogSampling(t *testing.T) {
+	t.Run("should log first and every nth attempt", func(t *testing.T) {
+		// given
+		logger, buf := newTestLogger()
+		sut := New(WithLogger(logger), WithLogSampling(3), WithBackoff(Constant(0)), WithMaxTries(8))
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 3)
+		assert.Contains(t, lines[0], "attempt=1 ")
+		assert.NotContains(t, lines[0], "suppressed")
+		assert.Contains(t, lines[1], "attempt=4 ")
+		assert.Contains(t, lines[1], "suppressed=2")
+		assert.Contains(t, lines[2], "attempt=7 ")
+	})
+	t.Run("should not log without logger", func(t *testing.T) {
+		// given
+		p := defaultPolicy()
+		WithLogSampling(3)(&p)
+
+		// then
+		assert.NotPanics(t, func() {
+			p.logAttempt(context.Background(), 1, assert.AnError, time.Second)
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// RunLoop supervises a daemon task: it executes fn forever until ctx is done. After a success, it waits for the
+// given interval; after a failure, it waits according to the backoff of r, which starts over after the next
+// success. The loop only ends early if fn returns a non-retriable error or an error wrapped with Abort, which is
+// returned then. Otherwise, RunLoop returns the error of ctx, including its cause. The limits set with WithMaxTries
+// and WithLimit do not apply as the loop runs indefinitely, whereas the spacing set with WithMinInterval does. If a
+// trigger is set with WithTrigger, every run additionally waits for an event after the interval or backoff.
+func RunLoop(ctx context.Context, r *Retrier, interval time.Duration, fn func(ctx context.Context) error) error {
+	failures := 0
+	var firstAttempt time.Time
+	for {
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+
+		// The policy is loaded anew for every run so that the daemon picks up changes made with Retrier.Update.
+		p := r.effectivePolicy(ctx, nil)
+		stats.attempts.Add(1)
+		attemptStart := p.now()
+		if failures == 0 {
+			firstAttempt = attemptStart
+		}
+		err := p.attempt(ctx, AttemptInfo{Number: failures + 1, FirstAttempt: firstAttempt}, fn)
+		var delay time.Duration
+		switch {
+		case err == nil:
+			failures = 0
+			delay = interval
+		case ctx.Err() != nil:
+			return contextError(ctx)
+		case IsAborted(err):
+			return err
+		default:
+			decision := p.classifier.Classify(err)
+			if !decision.Retry {
+				return err
+			}
+			failures++
+			stats.retries.Add(1)
+			delay = decision.Delay
+			if delay <= 0 {
+				delay = p.delay(failures)
+			}
+		}
+
+		kicked := r.kicks.wait()
+		if !p.sleep(ctx, p.spaced(delay, attemptStart), kicked) || !r.awaitTrigger(ctx, p, kicked) {
+			return contextError(ctx)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RunLoop(t *testing.T) {
+	t.Run("should run until context is done", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(1))
+		calls := 0
+
+		// when
+		err := RunLoop(ctx, sut, time.Millisecond, func(ctx context.Context) error {
+			calls++
+			if calls == 10 {
+				cancel()
+			}
+			if calls%2 == 0 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 10, calls)
+	})
+	t.Run("should back off on failures and reset after success", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var delays []time.Duration
+		backoff := BackoffFunc(func(attempt int) time.Duration {
+			delays = append(delays, time.Duration(attempt))
+			return time.Duration(attempt)
+		})
+		sut := New(WithBackoff(backoff))
+		results := []error{assert.AnError, assert.AnError, nil, assert.AnError}
+		calls := 0
+
+		// when
+		err := RunLoop(ctx, sut, time.Microsecond, func(ctx context.Context) error {
+			if calls == len(results) {
+				cancel()
+				return nil
+			}
+			result := results[calls]
+			calls++
+			return result
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []time.Duration{1, 2, 1}, delays)
+	})
+	t.Run("should stop on non-retriable error", func(t *testing.T) {
+		// given
+		permanent := errors.New("permanent")
+		sut := New(WithRetriable(func(err error) bool {
+			return !errors.Is(err, permanent)
+		}))
+
+		// when
+		err := RunLoop(context.Background(), sut, time.Millisecond, func(ctx context.Context) error {
+			return permanent
+		})
+
+		// then
+		assert.ErrorIs(t, err, permanent)
+	})
+	t.Run("should stop on abort", func(t *testing.T) {
+		// given
+		sut := New()
+
+		// when
+		err := RunLoop(context.Background(), sut, time.Millisecond, func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
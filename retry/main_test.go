@@ -0,0 +1,13 @@
+package retry
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// TestMain fails the tests of the package if a goroutine, e.g. a background refresh of Once or the poller of
+// WatchFile, outlives them.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Once lazily initializes a value, e.g. a client connecting to a remote service. Unlike sync.OnceValue, a failed
+// initialization is not cached: the next call of Get tries again. Only the first success is cached and returned by
+// all further calls unless the Once was created with a TTL by NewOnceWithTTL. A Once is safe for concurrent use.
+type Once[T any] struct {
+	retrier *Retrier
+	init    func(ctx context.Context) (T, error)
+	ttl     time.Duration
+
+	current    atomic.Pointer[onceValue[T]]
+	refreshing atomic.Bool
+	// sem guards the initialization. It is a channel instead of a mutex so that waiting callers honour their context.
+	sem chan struct{}
+
+	// mu guards the fields controlling the background refresh.
+	mu            sync.Mutex
+	closed        bool
+	cancelRefresh context.CancelFunc
+	refreshDone   chan struct{}
+}
+
+// onceValue is a successfully initialized value of a Once.
+type onceValue[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// NewOnce creates a Once initializing its value with init, which is retried according to the given Retrier. Nothing
+// is initialized until the first call of Get.
+func NewOnce[T any](retrier *Retrier, init func(ctx context.Context) (T, error)) *Once[T] {
+	return NewOnceWithTTL(retrier, 0, init)
+}
+
+// NewOnceWithTTL creates a Once whose value is refreshed with init once it is older than ttl, e.g. a token or a
+// configuration fetched from a remote service. The refresh runs in the background under the retry policy of the
+// given Retrier while Get keeps returning the stale value. If the refresh fails, the stale value is kept and the
+// next call of Get starts another refresh. A ttl of zero or less never refreshes the value.
+func NewOnceWithTTL[T any](retrier *Retrier, ttl time.Duration, init func(ctx context.Context) (T, error)) *Once[T] {
+	return &Once[T]{
+		retrier: retrier,
+		init:    init,
+		ttl:     ttl,
+		sem:     make(chan struct{}, 1),
+	}
+}
+
+// Get returns the value once it was initialized successfully. Otherwise, it runs the initialization with retries and
+// returns the error of the retry loop if it fails. Concurrent callers wait for a running initialization instead of
+// starting their own one. If the value has expired, Get returns it nevertheless and refreshes it in the background.
+func (o *Once[T]) Get(ctx context.Context) (T, error) {
+	if current := o.current.Load(); current != nil {
+		o.refreshIfExpired(ctx, current)
+		return current.value, nil
+	}
+
+	var zero T
+	select {
+	case o.sem <- struct{}{}:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	defer func() { <-o.sem }()
+
+	if current := o.current.Load(); current != nil {
+		return current.value, nil
+	}
+
+	current, err := o.load(ctx)
+	if err != nil {
+		return zero, err
+	}
+	return current.value, nil
+}
+
+// load executes the initialization with retries and stores its result on success.
+func (o *Once[T]) load(ctx context.Context) (*onceValue[T], error) {
+	var value T
+	err := o.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = o.init(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current := &onceValue[T]{value: value}
+	if o.ttl > 0 {
+		current.expires = o.retrier.policy.Load().now().Add(o.ttl)
+	}
+	o.current.Store(current)
+	return current, nil
+}
+
+// refreshIfExpired starts a background refresh of current if it has expired and no refresh is running yet. The
+// refresh keeps the values of ctx but is not canceled with it, as it outlives the call of Get.
+func (o *Once[T]) refreshIfExpired(ctx context.Context, current *onceValue[T]) {
+	if o.ttl <= 0 || o.retrier.policy.Load().now().Before(current.expires) || !o.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		o.refreshing.Store(false)
+		return
+	}
+
+	refreshCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	o.cancelRefresh = cancel
+	o.refreshDone = done
+	go func() {
+		defer close(done)
+		defer o.refreshing.Store(false)
+		defer cancel()
+		_, _ = o.load(refreshCtx)
+	}()
+}
+
+// Drain stops the background refreshes of a Once created with NewOnceWithTTL and waits until a running refresh has
+// finished, e.g. during the graceful shutdown of a pod. Get keeps returning the current value afterward. If ctx is
+// done before the refresh finished, Drain returns the error of ctx and leaves the refresh running.
+func (o *Once[T]) Drain(ctx context.Context) error {
+	o.mu.Lock()
+	o.closed = true
+	done := o.refreshDone
+	o.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close works like Drain but cancels a running refresh once ctx is done and waits until it has returned, so that no
+// background work outlives the call. It returns the error of ctx if the refresh had to be canceled.
+func (o *Once[T]) Close(ctx context.Context) error {
+	err := o.Drain(ctx)
+	if err == nil {
+		return nil
+	}
+
+	o.mu.Lock()
+	cancel, done := o.cancelRefresh, o.refreshDone
+	o.mu.Unlock()
+	cancel()
+	<-done
+	return err
+}
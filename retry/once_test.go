@@ -0,0 +1,273 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Once_Get(t *testing.T) {
+	fastRetrier := func() *Retrier {
+		return New(WithBackoff(Constant(0)), WithMaxTries(2))
+	}
+
+	t.Run("should initialize with retries and cache success", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := NewOnce(fastRetrier(), func(ctx context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", assert.AnError
+			}
+			return "client", nil
+		})
+
+		// when
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		second, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "client", first)
+		assert.Equal(t, "client", second)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("should retry failed initialization on next call", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := NewOnce(fastRetrier(), func(ctx context.Context) (int, error) {
+			calls++
+			if calls <= 2 {
+				return 0, assert.AnError
+			}
+			return 42, nil
+		})
+
+		// when
+		_, firstErr := sut.Get(context.Background())
+		value, err := sut.Get(context.Background())
+
+		// then
+		var exhaustedErr *ExhaustedError
+		assert.ErrorAs(t, firstErr, &exhaustedErr)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("should initialize only once for concurrent callers", func(t *testing.T) {
+		// given
+		calls := &atomic.Int32{}
+		sut := NewOnce(fastRetrier(), func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return 1, nil
+		})
+
+		// when
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = sut.Get(context.Background())
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should honour context while waiting for running initialization", func(t *testing.T) {
+		// given
+		started := make(chan struct{})
+		release := make(chan struct{})
+		sut := NewOnce(fastRetrier(), func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		go func() { _, _ = sut.Get(context.Background()) }()
+		<-started
+		defer close(release)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		_, err := sut.Get(ctx)
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func Test_NewOnceWithTTL(t *testing.T) {
+	t.Run("should serve stale value while refreshing in background", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		calls := &atomic.Int32{}
+		refreshed := make(chan struct{})
+		sut := NewOnceWithTTL(New(WithClock(clock), WithBackoff(Constant(0))), time.Minute, func(ctx context.Context) (int32, error) {
+			call := calls.Add(1)
+			if call > 1 {
+				defer close(refreshed)
+			}
+			return call, nil
+		})
+		first, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		clock.mu.Lock()
+		clock.now = clock.now.Add(2 * time.Minute)
+		clock.mu.Unlock()
+
+		// when
+		stale, err := sut.Get(context.Background())
+		<-refreshed
+		require.Eventually(t, func() bool { return !sut.refreshing.Load() }, time.Second, time.Millisecond)
+		fresh, freshErr := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, freshErr)
+		assert.Equal(t, int32(1), first)
+		assert.Equal(t, int32(1), stale)
+		assert.Equal(t, int32(2), fresh)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should keep stale value if refresh fails", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		calls := &atomic.Int32{}
+		sut := NewOnceWithTTL(New(WithClock(clock), WithBackoff(Constant(0)), WithMaxTries(2)), time.Minute, func(ctx context.Context) (string, error) {
+			if calls.Add(1) == 1 {
+				return "initial", nil
+			}
+			return "", assert.AnError
+		})
+		_, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		clock.mu.Lock()
+		clock.now = clock.now.Add(2 * time.Minute)
+		clock.mu.Unlock()
+
+		// when
+		_, _ = sut.Get(context.Background())
+		require.Eventually(t, func() bool { return calls.Load() == 3 && !sut.refreshing.Load() }, time.Second, time.Millisecond)
+		value, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "initial", value)
+	})
+	t.Run("should not refresh before expiry", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := NewOnceWithTTL(New(), time.Hour, func(ctx context.Context) (int, error) {
+			calls++
+			return calls, nil
+		})
+
+		// when
+		_, _ = sut.Get(context.Background())
+		value, err := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func Test_Once_Drain(t *testing.T) {
+	t.Run("should wait for running refresh and stop further ones", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		calls := &atomic.Int32{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		sut := NewOnceWithTTL(New(WithClock(clock), WithBackoff(Constant(0))), time.Minute, func(ctx context.Context) (int32, error) {
+			call := calls.Add(1)
+			if call == 2 {
+				close(started)
+				<-release
+			}
+			return call, nil
+		})
+		_, err := sut.Get(context.Background())
+		require.NoError(t, err)
+		clock.mu.Lock()
+		clock.now = clock.now.Add(2 * time.Minute)
+		clock.mu.Unlock()
+		_, _ = sut.Get(context.Background())
+		<-started
+
+		// when
+		drained := make(chan error)
+		go func() { drained <- sut.Drain(context.Background()) }()
+		close(release)
+		err = <-drained
+		clock.mu.Lock()
+		clock.now = clock.now.Add(2 * time.Minute)
+		clock.mu.Unlock()
+		value, getErr := sut.Get(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, getErr)
+		assert.Equal(t, int32(2), value)
+		assert.False(t, sut.refreshing.Load())
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should return immediately without refresh", func(t *testing.T) {
+		// given
+		sut := NewOnce(New(), func(ctx context.Context) (string, error) {
+			return "value", nil
+		})
+
+		// when
+		err := sut.Drain(context.Background())
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_Once_Close(t *testing.T) {
+	// given
+	clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+	calls := &atomic.Int32{}
+	started := make(chan struct{})
+	var refreshErr error
+	sut := NewOnceWithTTL(New(WithClock(clock), WithBackoff(Constant(0))), time.Minute, func(ctx context.Context) (int32, error) {
+		call := calls.Add(1)
+		if call == 2 {
+			close(started)
+			<-ctx.Done()
+			refreshErr = ctx.Err()
+			return 0, ctx.Err()
+		}
+		return call, nil
+	})
+	_, err := sut.Get(context.Background())
+	require.NoError(t, err)
+	clock.mu.Lock()
+	clock.now = clock.now.Add(2 * time.Minute)
+	clock.mu.Unlock()
+	_, _ = sut.Get(context.Background())
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// when
+	err = sut.Close(ctx)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, refreshErr, context.Canceled)
+	assert.False(t, sut.refreshing.Load())
+}
@@ -0,0 +1,164 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Option configures the behaviour of a Retrier.
+type Option func(*policy)
+
+type policy struct {
+	maxTries          int
+	initialWait       time.Duration
+	minInterval       time.Duration
+	maxRetryRate      float64
+	limit             time.Duration
+	backoff           Backoff
+	jitterPercent     float64
+	fullJitter        bool
+	jitterIdentity    string
+	monotonic         bool
+	classifier        Classifier
+	watchdogThreshold time.Duration
+	onStuck           func(elapsed time.Duration, attempt int)
+	attemptTimeout    time.Duration
+	cleanup           func(ctx context.Context, err error)
+	beforeAttempt     func(ctx context.Context, attempt int) error
+	onRetry           func(ctx context.Context, attempt int, err error, delay time.Duration)
+	onRecovered       func(attempts int, firstErr error)
+	atMostOnce        bool
+	checkOutcome      func(ctx context.Context) (bool, error)
+	stackTraces       bool
+	profilerLabels    bool
+	recordTimeline    bool
+	timelineWriter    io.Writer
+	operation         string
+	auditor           Auditor
+	reporter          ErrorReporter
+	clock             Clock
+	trigger           <-chan struct{}
+	onProgress        func(Progress)
+	progressWindow    time.Duration
+	logger            *slog.Logger
+	logEveryN         int
+	healthThreshold   int
+	cooldown          time.Duration
+	onHealthChange    func(healthy bool)
+}
+
+// compiledDefaultPolicy is built once so that creating a Retrier does not box the default backoff and classifier
+// into their interfaces again.
+var compiledDefaultPolicy = policy{
+	limit:      3 * time.Minute,
+	backoff:    defaultBackoff,
+	classifier: Predicate(AlwaysRetryFunc),
+}
+
+func defaultPolicy() policy {
+	return compiledDefaultPolicy
+}
+
+// delay returns the time to wait after the given attempt. Delays of a Schedule are not jittered, as they target a
+// point in time.
+func (p *policy) delay(attempt int) time.Duration {
+	delay, ok := p.nextDelay(attempt)
+	if !ok {
+		return math.MaxInt64
+	}
+	return delay
+}
+
+// nextDelay works like delay but returns false instead of the maximum duration if the backoff is a Schedule without
+// further point in time.
+func (p *policy) nextDelay(attempt int) (time.Duration, bool) {
+	if schedule, ok := p.backoff.(Schedule); ok {
+		return schedule.delayAt(p.now())
+	}
+
+	delay := p.backoff.Delay(attempt)
+	if p.fullJitter {
+		if p.jitterIdentity != "" {
+			return fullJitter(delay, identityRandom(p.jitterIdentity, attempt)), true
+		}
+		return fullJitter(delay, rand.Float64()), true
+	}
+	if p.jitterIdentity != "" {
+		return scaleJitter(delay, p.jitterPercent, identityRandom(p.jitterIdentity, attempt)), true
+	}
+	return applyJitter(delay, p.jitterPercent), true
+}
+
+// WithMaxTries limits the number of times a workload is executed. A value of zero or less removes the limit.
+func WithMaxTries(maxTries int) Option {
+	return func(p *policy) {
+		p.maxTries = maxTries
+	}
+}
+
+// WithInitialWait pauses for the given duration before the first attempt, e.g. to let a freshly created resource
+// warm up. By default, the first attempt starts immediately. The pause counts toward the limit set with WithLimit.
+func WithInitialWait(wait time.Duration) Option {
+	return func(p *policy) {
+		p.initialWait = wait
+	}
+}
+
+// WithMinInterval ensures that at least the given interval elapses between the starts of two attempts, also if the
+// backoff would retry sooner or the workload fails within microseconds. This protects downstream services from
+// retries spinning at full speed. Retrier.Kick still starts the next attempt right away.
+func WithMinInterval(interval time.Duration) Option {
+	return func(p *policy) {
+		p.minInterval = interval
+	}
+}
+
+// spaced extends delay so that the next attempt starts no earlier than the minimum interval after attemptStart.
+func (p *policy) spaced(delay time.Duration, attemptStart time.Time) time.Duration {
+	return max(delay, p.minInterval-p.since(attemptStart))
+}
+
+// WithLimit limits the total time a workload is retried. No further attempt is started if its preceding backoff
+// would exceed the limit. A value of zero or less removes the limit.
+func WithLimit(limit time.Duration) Option {
+	return func(p *policy) {
+		p.limit = limit
+	}
+}
+
+// WithBackoff sets the strategy computing the delay between two attempts. Delays below 100 microseconds are
+// waited for without a timer, which makes the Retrier suitable for in-memory operations like compare-and-swap loops.
+func WithBackoff(backoff Backoff) Option {
+	return func(p *policy) {
+		p.backoff = backoff
+	}
+}
+
+// WithRetriable sets the function that decides whether an error is worth another attempt. Please see
+// AlwaysRetryFunc and TestableRetryFunc for predefined functions.
+func WithRetriable(retriable func(error) bool) Option {
+	return func(p *policy) {
+		p.classifier = Predicate(retriable)
+	}
+}
+
+// WithWatchdog invokes onStuck once if a single retry loop is still running after the given threshold. This helps
+// to surface loops that silently retry for a very long time. If onStuck is nil, a warning is logged instead.
+func WithWatchdog(threshold time.Duration, onStuck func(elapsed time.Duration, attempt int)) Option {
+	if onStuck == nil {
+		onStuck = logStuckLoop
+	}
+
+	return func(p *policy) {
+		p.watchdogThreshold = threshold
+		p.onStuck = onStuck
+	}
+}
+
+func logStuckLoop(elapsed time.Duration, attempt int) {
+	slog.Warn("retry loop is still running", "elapsed", elapsed, "attempt", attempt)
+}
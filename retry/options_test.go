@@ -0,0 +1,195 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithWatchdog(t *testing.T) {
+	t.Run("should report a long running loop", func(t *testing.T) {
+		// given
+		reported := make(chan int, 1)
+		sut := New(WithWatchdog(10*time.Millisecond, func(elapsed time.Duration, attempt int) {
+			reported <- attempt
+		}))
+		fn := func(ctx context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		select {
+		case attempt := <-reported:
+			assert.Equal(t, 1, attempt)
+		case <-time.After(time.Second):
+			t.Fatal("watchdog did not report the loop")
+		}
+	})
+	t.Run("should not report a quick loop", func(t *testing.T) {
+		// given
+		reported := make(chan int, 1)
+		sut := New(WithWatchdog(50*time.Millisecond, func(elapsed time.Duration, attempt int) {
+			reported <- attempt
+		}))
+		fn := func(ctx context.Context) error {
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		select {
+		case <-reported:
+			t.Fatal("watchdog reported a finished loop")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+	t.Run("should log without callback", func(t *testing.T) {
+		// given
+		sut := New(WithWatchdog(time.Millisecond, nil))
+		fn := func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_WithBackoff(t *testing.T) {
+	t.Run("should retry with microsecond delays", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(10*time.Microsecond)), WithMaxTries(100))
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			if calls < 100 {
+				return assert.AnError
+			}
+			return nil
+		}
+		start := time.Now()
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 100, calls)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+}
+
+func Test_WithInitialWait(t *testing.T) {
+	t.Run("should wait before first attempt", func(t *testing.T) {
+		// given
+		sut := New(WithInitialWait(20 * time.Millisecond))
+		start := time.Now()
+		var firstAttempt time.Duration
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			firstAttempt = time.Since(start)
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, firstAttempt, 20*time.Millisecond)
+	})
+	t.Run("should start immediately by default", func(t *testing.T) {
+		// given
+		sut := New()
+		start := time.Now()
+		var firstAttempt time.Duration
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			firstAttempt = time.Since(start)
+			return nil
+		})
+
+		// then
+		assert.Less(t, firstAttempt, 10*time.Millisecond)
+	})
+	t.Run("should stop on canceled context", func(t *testing.T) {
+		// given
+		sut := New(WithInitialWait(time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		called := false
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, called)
+	})
+}
+
+func Test_WithMinInterval(t *testing.T) {
+	t.Run("should space attempts failing instantly", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithMinInterval(10*time.Millisecond))
+		var starts []time.Time
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			starts = append(starts, time.Now())
+			return assert.AnError
+		})
+
+		// then
+		require.Len(t, starts, 3)
+		assert.GreaterOrEqual(t, starts[1].Sub(starts[0]), 10*time.Millisecond)
+		assert.GreaterOrEqual(t, starts[2].Sub(starts[1]), 10*time.Millisecond)
+	})
+	t.Run("should not extend slow attempts", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMinInterval(10*time.Millisecond))
+		attemptStart := time.Now()
+
+		// when
+		actual := sut.policy.Load().spaced(time.Millisecond, attemptStart.Add(-20*time.Millisecond))
+
+		// then
+		assert.Equal(t, time.Millisecond, actual)
+	})
+	t.Run("should space daemon runs", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		sut := New(WithMinInterval(10 * time.Millisecond))
+		var starts []time.Time
+
+		// when
+		_ = RunLoop(ctx, sut, 0, func(ctx context.Context) error {
+			starts = append(starts, time.Now())
+			if len(starts) == 2 {
+				cancel()
+			}
+			return nil
+		})
+
+		// then
+		require.Len(t, starts, 2)
+		assert.GreaterOrEqual(t, starts[1].Sub(starts[0]), 10*time.Millisecond)
+	})
+}
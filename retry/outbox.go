@@ -0,0 +1,229 @@
+package retry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// outboxBatchSize limits the number of entries delivered by a single call of Outbox.Flush.
+const outboxBatchSize = 100
+
+// OutboxEntry is a side effect recorded in an Outbox, e.g. an HTTP call or an event to publish.
+type OutboxEntry struct {
+	// ID identifies the entry. It is assigned by Outbox.Enqueue.
+	ID string `json:"id"`
+	// Kind tells the delivery function how to interpret the payload, e.g. "webhook" or "dogu-installed".
+	Kind string `json:"kind"`
+	// Payload describes the side effect, e.g. as JSON.
+	Payload []byte `json:"payload"`
+	// Created is the time the entry was enqueued.
+	Created time.Time `json:"created"`
+	// Attempts is the number of failed deliveries so far.
+	Attempts int `json:"attempts"`
+	// NextAttempt is the time the next delivery is due.
+	NextAttempt time.Time `json:"nextAttempt"`
+	// LastError is the error of the last failed delivery.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// OutboxStore persists the entries of an Outbox, e.g. in the database whose transactions cause the side effects, so
+// that they survive restarts. Implementations must be safe for concurrent use.
+type OutboxStore interface {
+	// Add persists a new entry.
+	Add(ctx context.Context, entry OutboxEntry) error
+	// Due returns up to limit entries whose NextAttempt is not after now, the oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]OutboxEntry, error)
+	// Update persists the changed delivery state of an entry.
+	Update(ctx context.Context, entry OutboxEntry) error
+	// Remove deletes a delivered or abandoned entry.
+	Remove(ctx context.Context, id string) error
+}
+
+// OutboxConfig configures the delivery of an Outbox.
+type OutboxConfig struct {
+	// Store persists the entries. It defaults to an in-memory store, which loses the entries on restarts.
+	Store OutboxStore
+	// Deliver performs the side effect of an entry. The entry counts as acknowledged once Deliver returns nil.
+	Deliver func(ctx context.Context, entry OutboxEntry) error
+	// DeadLetter is called with entries whose delivery is abandoned, either because the error was not retriable or
+	// the limits of the Retrier were reached. The entry is removed from the store afterward. It is optional.
+	DeadLetter func(ctx context.Context, entry OutboxEntry, err error)
+}
+
+// Outbox records intended side effects in a store and delivers them with retries until they are acknowledged. This
+// gives at-least-once semantics for critical notifications that must not get lost if the receiver is down or the
+// process restarts, so Deliver must tolerate duplicates. In contrast to Retrier.Do, the delays between the
+// deliveries are kept in the store, so that they may span hours.
+//
+// The errors of Deliver are classified by the Retrier, its backoff computes the delays and its limits abandon an
+// entry. The limit set with WithLimit counts from the creation of the entry, so outboxes usually configure a longer
+// one than the default. Only a single worker should deliver the entries of a store.
+type Outbox struct {
+	retrier *Retrier
+	config  OutboxConfig
+}
+
+// NewOutbox creates an Outbox delivering its entries with the given Retrier.
+func NewOutbox(retrier *Retrier, config OutboxConfig) *Outbox {
+	if config.Store == nil {
+		config.Store = &memoryOutboxStore{}
+	}
+	return &Outbox{retrier: retrier, config: config}
+}
+
+// Enqueue records a side effect of the given kind, which is due for delivery immediately.
+func (o *Outbox) Enqueue(ctx context.Context, kind string, payload []byte) error {
+	id, err := newOutboxID()
+	if err != nil {
+		return err
+	}
+
+	now := o.retrier.policy.Load().now()
+	entry := OutboxEntry{ID: id, Kind: kind, Payload: payload, Created: now, NextAttempt: now}
+	if err := o.config.Store.Add(ctx, entry); err != nil {
+		return fmt.Errorf("failed to add outbox entry: %w", err)
+	}
+	return nil
+}
+
+// Run delivers the due entries every interval until ctx is done and returns the error of ctx then. Errors of the
+// store are returned immediately.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if err := o.Flush(ctx); err != nil {
+			return err
+		}
+		if !o.retrier.policy.Load().sleep(ctx, interval, nil) {
+			return contextError(ctx)
+		}
+	}
+}
+
+// Flush delivers the entries that are due now once. Failed deliveries are rescheduled according to the Retrier or
+// abandoned. Only errors of the store are returned.
+func (o *Outbox) Flush(ctx context.Context) error {
+	p := o.retrier.policy.Load()
+	due, err := o.config.Store.Due(ctx, p.now(), outboxBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to load due outbox entries: %w", err)
+	}
+
+	for _, entry := range due {
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		if err := o.deliver(ctx, p, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver performs the side effect of entry and updates the store according to the outcome.
+func (o *Outbox) deliver(ctx context.Context, p *policy, entry OutboxEntry) error {
+	deliveryErr := o.config.Deliver(ctx, entry)
+	if deliveryErr == nil {
+		return o.remove(ctx, entry)
+	}
+
+	entry.Attempts++
+	entry.LastError = deliveryErr.Error()
+	decision := p.classifier.Classify(deliveryErr)
+	if IsAborted(deliveryErr) || !decision.Retry {
+		return o.abandon(ctx, entry, deliveryErr)
+	}
+	if p.maxTries > 0 && entry.Attempts >= p.maxTries {
+		return o.abandon(ctx, entry, &ExhaustedError{Attempts: entry.Attempts, Err: deliveryErr})
+	}
+
+	delay := decision.Delay
+	if delay <= 0 {
+		delay = p.delay(entry.Attempts)
+	}
+	entry.NextAttempt = p.now().Add(delay)
+	if p.limit > 0 && entry.NextAttempt.Sub(entry.Created) > p.limit {
+		return o.abandon(ctx, entry, &ExhaustedError{Attempts: entry.Attempts, Err: deliveryErr})
+	}
+	if err := o.config.Store.Update(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update outbox entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+func (o *Outbox) abandon(ctx context.Context, entry OutboxEntry, err error) error {
+	if o.config.DeadLetter != nil {
+		o.config.DeadLetter(ctx, entry, err)
+	}
+	return o.remove(ctx, entry)
+}
+
+func (o *Outbox) remove(ctx context.Context, entry OutboxEntry) error {
+	if err := o.config.Store.Remove(ctx, entry.ID); err != nil {
+		return fmt.Errorf("failed to remove outbox entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+func newOutboxID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate outbox entry id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// errOutboxEntryNotFound is returned by the in-memory store for unknown entries.
+var errOutboxEntryNotFound = errors.New("outbox entry not found")
+
+// memoryOutboxStore keeps the entries of an Outbox in memory.
+type memoryOutboxStore struct {
+	mu      sync.Mutex
+	entries []OutboxEntry
+}
+
+func (s *memoryOutboxStore) Add(_ context.Context, entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryOutboxStore) Due(_ context.Context, now time.Time, limit int) ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []OutboxEntry
+	for _, entry := range s.entries {
+		if !entry.NextAttempt.After(now) && len(due) < limit {
+			due = append(due, entry)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryOutboxStore) Update(_ context.Context, entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.entries, func(e OutboxEntry) bool { return e.ID == entry.ID })
+	if i < 0 {
+		return errOutboxEntryNotFound
+	}
+	s.entries[i] = entry
+	return nil
+}
+
+func (s *memoryOutboxStore) Remove(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = slices.DeleteFunc(s.entries, func(e OutboxEntry) bool { return e.ID == id })
+	return nil
+}
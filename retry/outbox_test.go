@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutboxClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+}
+
+func Test_Outbox(t *testing.T) {
+	t.Run("should deliver and remove entry", func(t *testing.T) {
+		// given
+		store := &memoryOutboxStore{}
+		var delivered []OutboxEntry
+		sut := NewOutbox(New(), OutboxConfig{Store: store, Deliver: func(ctx context.Context, entry OutboxEntry) error {
+			delivered = append(delivered, entry)
+			return nil
+		}})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", []byte(`{"dogu":"ldap"}`)))
+
+		// when
+		err := sut.Flush(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, delivered, 1)
+		assert.Equal(t, "webhook", delivered[0].Kind)
+		assert.Equal(t, `{"dogu":"ldap"}`, string(delivered[0].Payload))
+		assert.Len(t, delivered[0].ID, 32)
+		assert.Empty(t, store.entries)
+	})
+	t.Run("should reschedule failed delivery with backoff", func(t *testing.T) {
+		// given
+		clock := newOutboxClock()
+		store := &memoryOutboxStore{}
+		failures := 2
+		deliveries := 0
+		sut := NewOutbox(New(WithClock(clock), WithBackoff(Constant(time.Hour)), WithLimit(0)), OutboxConfig{Store: store, Deliver: func(ctx context.Context, entry OutboxEntry) error {
+			deliveries++
+			if failures > 0 {
+				failures--
+				return assert.AnError
+			}
+			return nil
+		}})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", nil))
+
+		// when
+		require.NoError(t, sut.Flush(context.Background()))
+		require.Len(t, store.entries, 1)
+		rescheduled := store.entries[0]
+		require.NoError(t, sut.Flush(context.Background()))
+		clock.advance(time.Hour)
+		require.NoError(t, sut.Flush(context.Background()))
+		clock.advance(time.Hour)
+		err := sut.Flush(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, rescheduled.Attempts)
+		assert.Equal(t, rescheduled.Created.Add(time.Hour), rescheduled.NextAttempt)
+		assert.Equal(t, assert.AnError.Error(), rescheduled.LastError)
+		assert.Equal(t, 3, deliveries)
+		assert.Empty(t, store.entries)
+	})
+	t.Run("should abandon entry after max tries", func(t *testing.T) {
+		// given
+		clock := newOutboxClock()
+		store := &memoryOutboxStore{}
+		var deadErr error
+		var dead OutboxEntry
+		sut := NewOutbox(New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithMaxTries(2)), OutboxConfig{
+			Store: store,
+			Deliver: func(ctx context.Context, entry OutboxEntry) error {
+				return assert.AnError
+			},
+			DeadLetter: func(ctx context.Context, entry OutboxEntry, err error) {
+				dead, deadErr = entry, err
+			},
+		})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", nil))
+
+		// when
+		require.NoError(t, sut.Flush(context.Background()))
+		clock.advance(time.Minute)
+		err := sut.Flush(context.Background())
+
+		// then
+		require.NoError(t, err)
+		var exhaustedErr *ExhaustedError
+		require.True(t, errors.As(deadErr, &exhaustedErr))
+		assert.Equal(t, 2, exhaustedErr.Attempts)
+		assert.Equal(t, 2, dead.Attempts)
+		assert.Empty(t, store.entries)
+	})
+	t.Run("should abandon entry after limit", func(t *testing.T) {
+		// given
+		clock := newOutboxClock()
+		var deadErr error
+		sut := NewOutbox(New(WithClock(clock), WithBackoff(Constant(time.Hour)), WithLimit(30*time.Minute)), OutboxConfig{
+			Deliver: func(ctx context.Context, entry OutboxEntry) error {
+				return assert.AnError
+			},
+			DeadLetter: func(ctx context.Context, entry OutboxEntry, err error) {
+				deadErr = err
+			},
+		})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", nil))
+
+		// when
+		err := sut.Flush(context.Background())
+
+		// then
+		require.NoError(t, err)
+		var exhaustedErr *ExhaustedError
+		assert.True(t, errors.As(deadErr, &exhaustedErr))
+	})
+	t.Run("should abandon entry on non-retriable error", func(t *testing.T) {
+		// given
+		store := &memoryOutboxStore{}
+		var deadErr error
+		sut := NewOutbox(New(), OutboxConfig{
+			Store: store,
+			Deliver: func(ctx context.Context, entry OutboxEntry) error {
+				return Abort(assert.AnError)
+			},
+			DeadLetter: func(ctx context.Context, entry OutboxEntry, err error) {
+				deadErr = err
+			},
+		})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", nil))
+
+		// when
+		err := sut.Flush(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.ErrorIs(t, deadErr, assert.AnError)
+		assert.Empty(t, store.entries)
+	})
+	t.Run("should deliver entries until canceled", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		sut := NewOutbox(New(), OutboxConfig{Deliver: func(ctx context.Context, entry OutboxEntry) error {
+			cancel()
+			return nil
+		}})
+		require.NoError(t, sut.Enqueue(context.Background(), "webhook", nil))
+
+		// when
+		err := sut.Run(ctx, time.Millisecond)
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
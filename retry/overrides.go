@@ -0,0 +1,41 @@
+package retry
+
+import "context"
+
+type policyOverrideKey struct{}
+
+// ContextWithPolicy attaches options to ctx that every Retrier applies on top of its own policy for retry loops
+// running with this context. This way, request-level requirements like interactive versus batch processing can
+// influence the retry behaviour through existing call chains. Options of nested calls are applied after the ones
+// of their parent context. The overrides cannot change which errors are retried: the classifier set with
+// WithRetriable or WithClassifier belongs to the workload of a Retrier, e.g. to the status code handling of
+// retryhttp.Transport, and is kept. Per-call options of Retrier.Do can still replace it.
+func ContextWithPolicy(ctx context.Context, opts ...Option) context.Context {
+	parent := policyOverrides(ctx)
+	combined := append(parent[:len(parent):len(parent)], opts...)
+	return context.WithValue(ctx, policyOverrideKey{}, combined)
+}
+
+func policyOverrides(ctx context.Context) []Option {
+	opts, _ := ctx.Value(policyOverrideKey{}).([]Option)
+	return opts
+}
+
+// effectivePolicy returns the policy of the Retrier with the overrides of ctx and then opts applied.
+func (r *Retrier) effectivePolicy(ctx context.Context, opts []Option) *policy {
+	p := r.policy.Load()
+	overrides := policyOverrides(ctx)
+	if len(overrides) == 0 && len(opts) == 0 {
+		return p
+	}
+
+	overridden := *p
+	for _, opt := range overrides {
+		opt(&overridden)
+	}
+	overridden.classifier = p.classifier
+	for _, opt := range opts {
+		opt(&overridden)
+	}
+	return &overridden
+}
@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContextWithPolicy(t *testing.T) {
+	t.Run("should override policy of retrier", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(5))
+		ctx := ContextWithPolicy(context.Background(), WithMaxTries(2))
+		calls := 0
+
+		// when
+		_ = sut.Do(ctx, func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 5, sut.policy.Load().maxTries)
+	})
+	t.Run("should apply nested overrides after parent ones", func(t *testing.T) {
+		// given
+		sut := New()
+		parent := ContextWithPolicy(context.Background(), WithMaxTries(2), WithLimit(time.Second))
+		child := ContextWithPolicy(parent, WithMaxTries(3))
+
+		// when
+		actual := sut.effectivePolicy(child, nil)
+		actualParent := sut.effectivePolicy(parent, nil)
+
+		// then
+		assert.Equal(t, 3, actual.maxTries)
+		assert.Equal(t, time.Second, actual.limit)
+		assert.Equal(t, 2, actualParent.maxTries)
+	})
+	t.Run("should keep classifier of retrier", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithRetriable(TestableRetryFunc))
+		ctx := ContextWithPolicy(context.Background(), WithRetriable(AlwaysRetryFunc))
+		calls := 0
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should use policy of retrier without overrides", func(t *testing.T) {
+		// given
+		sut := New()
+
+		// when
+		actual := sut.effectivePolicy(context.Background(), nil)
+
+		// then
+		assert.Same(t, sut.policy.Load(), actual)
+	})
+}
+
+func Test_Retrier_Do_perCallOptions(t *testing.T) {
+	t.Run("should override policy for single call", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(5))
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		_ = sut.Do(context.Background(), fn, WithMaxTries(1))
+		callsWithOverride := calls
+		_ = sut.Do(context.Background(), fn)
+
+		// then
+		assert.Equal(t, 1, callsWithOverride)
+		assert.Equal(t, 6, calls)
+	})
+	t.Run("should apply call options after context options", func(t *testing.T) {
+		// given
+		sut := New()
+		ctx := ContextWithPolicy(context.Background(), WithMaxTries(2), WithLimit(time.Second))
+
+		// when
+		actual := sut.effectivePolicy(ctx, []Option{WithMaxTries(4)})
+
+		// then
+		assert.Equal(t, 4, actual.maxTries)
+		assert.Equal(t, time.Second, actual.limit)
+	})
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PartTracker records which parts of a chunked transfer, e.g. a multipart upload, completed already. A persistent
+// implementation allows resuming a transfer even after a restart of the process. Implementations must be safe for
+// concurrent use.
+type PartTracker interface {
+	// Done returns whether the given part completed already.
+	Done(part int) bool
+	// MarkDone records that the given part completed.
+	MarkDone(part int)
+}
+
+// DoParts transfers the parts 0 to parts-1 with transfer and retries only the parts that failed. Every attempt
+// transfers all parts not marked done in tracker; the failures of an attempt are joined to a single error which is
+// classified like any other error of the Retrier. If tracker is nil, the progress is kept in memory. The number of
+// completed parts is reported with ReportProgress.
+func (r *Retrier) DoParts(ctx context.Context, parts int, tracker PartTracker, transfer func(ctx context.Context, part int) error) error {
+	if tracker == nil {
+		tracker = &memoryPartTracker{done: map[int]bool{}}
+	}
+
+	return r.Do(ctx, func(ctx context.Context) error {
+		done := 0
+		for part := 0; part < parts; part++ {
+			if tracker.Done(part) {
+				done++
+			}
+		}
+		ReportProgress(ctx, Progress{Items: done, TotalItems: parts})
+
+		var errs []error
+		for part := 0; part < parts; part++ {
+			if tracker.Done(part) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := transfer(ctx, part); err != nil {
+				errs = append(errs, fmt.Errorf("part %d: %w", part, err))
+				continue
+			}
+			tracker.MarkDone(part)
+			done++
+			ReportProgress(ctx, Progress{Items: done, TotalItems: parts})
+		}
+		return errors.Join(errs...)
+	})
+}
+
+type memoryPartTracker struct {
+	mu   sync.Mutex
+	done map[int]bool
+}
+
+func (t *memoryPartTracker) Done(part int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.done[part]
+}
+
+func (t *memoryPartTracker) MarkDone(part int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done[part] = true
+}
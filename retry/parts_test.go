@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retrier_DoParts(t *testing.T) {
+	t.Run("should only retry failed parts", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)))
+		transferred := map[int]int{}
+		transfer := func(ctx context.Context, part int) error {
+			transferred[part]++
+			if part == 2 && transferred[part] < 3 {
+				return assert.AnError
+			}
+			return nil
+		}
+
+		// when
+		err := sut.DoParts(context.Background(), 4, nil, transfer)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 3, 3: 1}, transferred)
+	})
+	t.Run("should skip parts done before", func(t *testing.T) {
+		// given
+		sut := New()
+		tracker := &memoryPartTracker{done: map[int]bool{0: true, 1: true}}
+		var transferred []int
+
+		// when
+		err := sut.DoParts(context.Background(), 3, tracker, func(ctx context.Context, part int) error {
+			transferred = append(transferred, part)
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, transferred)
+		assert.True(t, tracker.Done(2))
+	})
+	t.Run("should join errors of failed parts", func(t *testing.T) {
+		// given
+		sut := New(WithMaxTries(1))
+		errPart := errors.New("part failed")
+
+		// when
+		err := sut.DoParts(context.Background(), 3, nil, func(ctx context.Context, part int) error {
+			if part == 1 {
+				return nil
+			}
+			return errPart
+		})
+
+		// then
+		assert.ErrorIs(t, err, errPart)
+		assert.ErrorContains(t, err, "part 0: part failed")
+		assert.ErrorContains(t, err, "part 2: part failed")
+	})
+}
@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+const (
+	// LabelOperation is the pprof label holding the operation set with WithOperation.
+	LabelOperation = "retry.operation"
+	// LabelAttempt is the pprof label holding the number of the attempt.
+	LabelAttempt = "retry.attempt"
+)
+
+// WithProfilerLabels tags the goroutine executing an attempt with the pprof labels LabelOperation and LabelAttempt,
+// so that CPU and goroutine profiles attribute the time spent in retried workloads to their logical operation.
+// Goroutines started by the workload inherit the labels. The operation label is omitted if no operation is set.
+func WithProfilerLabels() Option {
+	return func(p *policy) {
+		p.profilerLabels = true
+	}
+}
+
+// labeled executes fn with the pprof labels of the attempt if they are enabled.
+func (p *policy) labeled(ctx context.Context, attempt int, fn func(ctx context.Context)) {
+	if !p.profilerLabels {
+		fn(ctx)
+		return
+	}
+
+	labels := []string{LabelAttempt, strconv.Itoa(attempt)}
+	if p.operation != "" {
+		labels = append(labels, LabelOperation, p.operation)
+	}
+	pprof.Do(ctx, pprof.Labels(labels...), fn)
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithProfilerLabels(t *testing.T) {
+	t.Run("should label attempts", func(t *testing.T) {
+		// given
+		sut := New(WithProfilerLabels(), WithOperation("backup"), WithBackoff(Constant(0)), WithMaxTries(2))
+		var attempts, operations []string
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			attempt, _ := pprof.Label(ctx, LabelAttempt)
+			operation, _ := pprof.Label(ctx, LabelOperation)
+			attempts = append(attempts, attempt)
+			operations = append(operations, operation)
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, []string{"1", "2"}, attempts)
+		assert.Equal(t, []string{"backup", "backup"}, operations)
+	})
+	t.Run("should label hooks and omit missing operation", func(t *testing.T) {
+		// given
+		var hookAttempt string
+		var hasOperation bool
+		sut := New(WithProfilerLabels(), WithBeforeAttempt(func(ctx context.Context, _ int) error {
+			hookAttempt, _ = pprof.Label(ctx, LabelAttempt)
+			_, hasOperation = pprof.Label(ctx, LabelOperation)
+			return nil
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(context.Context) error { return nil })
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "1", hookAttempt)
+		assert.False(t, hasOperation)
+	})
+	t.Run("should not label by default", func(t *testing.T) {
+		// given
+		sut := New()
+		var labeled bool
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			_, labeled = pprof.Label(ctx, LabelAttempt)
+			return nil
+		})
+
+		// then
+		assert.False(t, labeled)
+	})
+}
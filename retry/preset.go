@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// PresetName names a vetted policy for teams without strong opinions on retries, see Preset.
+type PresetName string
+
+const (
+	// PresetConservative makes 3 attempts with delays from 1 to 30 seconds and ±20% jitter within 5 minutes. It
+	// suits expensive or non-critical operations that should put little extra load on a struggling upstream.
+	PresetConservative PresetName = "conservative"
+	// PresetStandard makes 5 attempts with delays from 500 milliseconds to 10 seconds and ±50% jitter within a
+	// minute. It suits most calls to other services.
+	PresetStandard PresetName = "standard"
+	// PresetAggressive makes 5 attempts within 30 seconds with delays growing from 100 milliseconds to 2 seconds,
+	// each picked randomly between zero and that value as set with WithFullJitter, so no delay exceeds 2 seconds. It
+	// suits cheap calls on the request path of users who would rather not wait long.
+	PresetAggressive PresetName = "aggressive"
+	// PresetKubernetesConflict is behaviorally identical to DefaultRetry of k8s.io/client-go/util/retry: 5 attempts
+	// with a delay of 10 milliseconds plus up to 10% jitter and no time limit. Combined with
+	// WithRetriable(k8s.IsConflict), it retries conflicts exactly like retry.RetryOnConflict(retry.DefaultRetry, fn).
+	PresetKubernetesConflict PresetName = "kubernetes-conflict"
+	// PresetKubernetesBackoff is behaviorally identical to DefaultBackoff of k8s.io/client-go/util/retry: 4 attempts
+	// with delays of 10, 50 and 250 milliseconds, each plus up to 10% jitter, and no time limit.
+	PresetKubernetesBackoff PresetName = "kubernetes-backoff"
+)
+
+var presets = map[PresetName][]Option{
+	PresetConservative: presetOptions(3, 5*time.Minute, time.Second, 30*time.Second, WithJitterPercent(20)),
+	PresetStandard:     presetOptions(5, time.Minute, 500*time.Millisecond, 10*time.Second, WithJitterPercent(50)),
+	PresetAggressive:   presetOptions(5, 30*time.Second, 100*time.Millisecond, 2*time.Second, WithFullJitter()),
+	PresetKubernetesConflict: {
+		WithMaxTries(5),
+		WithLimit(0),
+		WithBackoff(clientGoBackoff{Duration: 10 * time.Millisecond, Factor: 1, Jitter: 0.1}),
+		WithJitterPercent(0),
+	},
+	PresetKubernetesBackoff: {
+		WithMaxTries(4),
+		WithLimit(0),
+		WithBackoff(clientGoBackoff{Duration: 10 * time.Millisecond, Factor: 5, Jitter: 0.1}),
+		WithJitterPercent(0),
+	},
+}
+
+func presetOptions(maxTries int, limit, initialDelay, maxDelay time.Duration, jitter Option) []Option {
+	return []Option{
+		WithMaxTries(maxTries),
+		WithLimit(limit),
+		WithBackoff(Exponential{Initial: initialDelay, Factor: 2, Max: maxDelay}),
+		jitter,
+	}
+}
+
+// Preset returns an option configuring the maximum tries, limit, backoff and jitter of the named preset.
+// Options following it override single aspects, e.g. New(Preset(PresetStandard), WithMaxTries(10)). Preset panics
+// if the name is unknown, so that a typo is noticed on startup. Use PresetName.Valid for names from user input.
+func Preset(name PresetName) Option {
+	opts, ok := presets[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown retry preset %q", name))
+	}
+
+	return func(p *policy) {
+		for _, opt := range opts {
+			opt(p)
+		}
+	}
+}
+
+// Valid reports whether a preset with the name exists.
+func (n PresetName) Valid() bool {
+	_, ok := presets[n]
+	return ok
+}
+
+// UnmarshalJSON decodes the name of a preset and rejects unknown ones.
+func (n *PresetName) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("preset must be a string: %w", err)
+	}
+	if !PresetName(value).Valid() {
+		return fmt.Errorf("unknown retry preset %q", value)
+	}
+	*n = PresetName(value)
+	return nil
+}
+
+// clientGoBackoff computes the delays of a wait.Backoff of k8s.io/apimachinery without a cap. Unlike the jitter set
+// with WithJitterPercent, its jitter only lengthens the delay, by up to the Jitter fraction.
+type clientGoBackoff struct {
+	Duration time.Duration
+	Factor   float64
+	Jitter   float64
+}
+
+// Delay returns the delay after the given attempt like the attempt-th call of wait.Backoff.Step.
+func (b clientGoBackoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Duration) * math.Pow(b.Factor, float64(attempt-1))
+	if b.Jitter > 0 {
+		delay += rand.Float64() * b.Jitter * delay
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
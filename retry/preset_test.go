@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Preset(t *testing.T) {
+	tests := []struct {
+		name     PresetName
+		maxTries int
+		limit    time.Duration
+		backoff  Exponential
+		jitter   float64
+		full     bool
+	}{
+		{PresetConservative, 3, 5 * time.Minute, Exponential{Initial: time.Second, Factor: 2, Max: 30 * time.Second}, 20, false},
+		{PresetStandard, 5, time.Minute, Exponential{Initial: 500 * time.Millisecond, Factor: 2, Max: 10 * time.Second}, 50, false},
+		{PresetAggressive, 5, 30 * time.Second, Exponential{Initial: 100 * time.Millisecond, Factor: 2, Max: 2 * time.Second}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			// given
+			p := defaultPolicy()
+
+			// when
+			Preset(tt.name)(&p)
+
+			// then
+			assert.Equal(t, tt.maxTries, p.maxTries)
+			assert.Equal(t, tt.limit, p.limit)
+			assert.Equal(t, tt.backoff, p.backoff)
+			assert.Equal(t, tt.jitter, p.jitterPercent)
+			assert.Equal(t, tt.full, p.fullJitter)
+		})
+	}
+
+	t.Run("should keep aggressive delays within maximum", func(t *testing.T) {
+		// given
+		sut := New(Preset(PresetAggressive))
+
+		// when
+		for range 1000 {
+			actual := sut.DelayFor(10)
+
+			// then
+			assert.GreaterOrEqual(t, actual, time.Duration(0))
+			assert.LessOrEqual(t, actual, 2*time.Second)
+		}
+	})
+
+	t.Run("should be overridden by following options", func(t *testing.T) {
+		// given
+		p := defaultPolicy()
+
+		// when
+		for _, opt := range []Option{Preset(PresetStandard), WithMaxTries(10), WithMaxDelay(time.Minute)} {
+			opt(&p)
+		}
+
+		// then
+		assert.Equal(t, 10, p.maxTries)
+		assert.Equal(t, Exponential{Initial: 500 * time.Millisecond, Factor: 2, Max: time.Minute}, p.backoff)
+	})
+	t.Run("should panic on unknown name", func(t *testing.T) {
+		assert.PanicsWithValue(t, `unknown retry preset "reckless"`, func() { Preset("reckless") })
+	})
+}
+
+func Test_PresetName_UnmarshalJSON(t *testing.T) {
+	t.Run("should decode known name", func(t *testing.T) {
+		// given
+		var sut PresetName
+
+		// when
+		err := json.Unmarshal([]byte(`"aggressive"`), &sut)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, PresetAggressive, sut)
+		assert.True(t, sut.Valid())
+	})
+	t.Run("should reject unknown name", func(t *testing.T) {
+		// given
+		var sut PresetName
+
+		// when
+		err := json.Unmarshal([]byte(`"reckless"`), &sut)
+		invalidErr := json.Unmarshal([]byte(`3`), &sut)
+
+		// then
+		assert.EqualError(t, err, `unknown retry preset "reckless"`)
+		assert.ErrorContains(t, invalidErr, "preset must be a string")
+		assert.False(t, PresetName("reckless").Valid())
+	})
+}
+
+func Test_clientGoBackoff_Delay(t *testing.T) {
+	t.Run("should only lengthen delays by jitter", func(t *testing.T) {
+		sut := clientGoBackoff{Duration: 10 * time.Millisecond, Factor: 5, Jitter: 0.1}
+		for range 100 {
+			actual := sut.Delay(3)
+			assert.GreaterOrEqual(t, actual, 250*time.Millisecond)
+			assert.LessOrEqual(t, actual, 275*time.Millisecond)
+		}
+	})
+	t.Run("should be exact without jitter", func(t *testing.T) {
+		sut := clientGoBackoff{Duration: 10 * time.Millisecond, Factor: 1}
+		assert.Equal(t, 10*time.Millisecond, sut.Delay(5))
+	})
+	t.Run("should not overflow", func(t *testing.T) {
+		sut := clientGoBackoff{Duration: time.Second, Factor: 10}
+		assert.Equal(t, time.Duration(math.MaxInt64), sut.Delay(1000))
+	})
+}
@@ -0,0 +1,17 @@
+package retry
+
+// PressureSource is implemented by components that track failing or retried calls, like Breaker, BreakerRegistry and
+// retryhttp.RetryBudget. Pressure returns the current retry pressure between 0 for none and 1 for components that
+// already reject calls. Admission logic like the intake of a queue uses it to shed load before retries pile up.
+type PressureSource interface {
+	Pressure() float64
+}
+
+// MaxPressure returns the highest pressure of the given sources, or 0 without sources.
+func MaxPressure(sources ...PressureSource) float64 {
+	pressure := 0.0
+	for _, source := range sources {
+		pressure = max(pressure, source.Pressure())
+	}
+	return pressure
+}
@@ -0,0 +1,26 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MaxPressure(t *testing.T) {
+	t.Run("should return highest pressure", func(t *testing.T) {
+		// given
+		idle := NewBreaker(2, time.Minute)
+		failing := NewBreaker(2, time.Minute)
+		failing.Failure()
+
+		// when
+		actual := MaxPressure(idle, failing)
+
+		// then
+		assert.Equal(t, 0.5, actual)
+	})
+	t.Run("should return zero without sources", func(t *testing.T) {
+		assert.Equal(t, 0.0, MaxPressure())
+	})
+}
@@ -0,0 +1,47 @@
+package retry
+
+import "context"
+
+// Progress describes the cumulative progress of a transfer across all attempts.
+type Progress struct {
+	// Bytes is the number of bytes transferred so far.
+	Bytes int64
+	// TotalBytes is the expected number of bytes or zero if unknown.
+	TotalBytes int64
+	// Items is the number of items, e.g. parts, transferred so far.
+	Items int
+	// TotalItems is the expected number of items or zero if unknown.
+	TotalItems int
+}
+
+// Percentage returns the progress in percent based on the bytes or, if their total is unknown, the items. It
+// returns zero if neither total is known.
+func (p Progress) Percentage() float64 {
+	switch {
+	case p.TotalBytes > 0:
+		return float64(p.Bytes) / float64(p.TotalBytes) * 100
+	case p.TotalItems > 0:
+		return float64(p.Items) / float64(p.TotalItems) * 100
+	default:
+		return 0
+	}
+}
+
+type progressKey struct{}
+
+// WithProgress sets a callback receiving the progress reported by the workload with ReportProgress. The resumable
+// helpers like DoParts report their progress cumulatively across attempts, so that a UI can show a single progress
+// bar for the whole retry loop.
+func WithProgress(onProgress func(Progress)) Option {
+	return func(p *policy) {
+		p.onProgress = onProgress
+	}
+}
+
+// ReportProgress passes the progress to the callback set with WithProgress. ctx must be the context handed to the
+// workload. Without a callback, ReportProgress does nothing.
+func ReportProgress(ctx context.Context, progress Progress) {
+	if onProgress, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		onProgress(progress)
+	}
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Progress_Percentage(t *testing.T) {
+	assert.Equal(t, 25.0, Progress{Bytes: 25, TotalBytes: 100, Items: 1, TotalItems: 2}.Percentage())
+	assert.Equal(t, 50.0, Progress{Items: 1, TotalItems: 2}.Percentage())
+	assert.Equal(t, 0.0, Progress{Bytes: 25}.Percentage())
+}
+
+func Test_WithProgress(t *testing.T) {
+	t.Run("should pass reported progress to callback", func(t *testing.T) {
+		// given
+		var reported []Progress
+		sut := New(WithProgress(func(progress Progress) {
+			reported = append(reported, progress)
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			ReportProgress(ctx, Progress{Bytes: 1})
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Progress{{Bytes: 1}}, reported)
+	})
+	t.Run("should report cumulative progress of parts across attempts", func(t *testing.T) {
+		// given
+		var reported []int
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithProgress(func(progress Progress) {
+			reported = append(reported, progress.Items)
+		}))
+		failed := false
+
+		// when
+		err := sut.DoParts(context.Background(), 3, nil, func(ctx context.Context, part int) error {
+			if part == 1 && !failed {
+				failed = true
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2, 2, 3}, reported)
+	})
+	t.Run("should ignore progress without callback", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			ReportProgress(context.Background(), Progress{Bytes: 1})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// WithMaxRetryRate caps the rate of retries across all concurrent retry loops of the Retrier, e.g. to five retries
+// per second, independently of the backoff of each loop. This keeps the aggregate pressure on a dependency bounded
+// if many callers fail at once. Retries exceeding the rate wait until they are due, which counts toward the limit
+// set with WithLimit. First attempts are never delayed. A rate of zero or less removes the cap.
+func WithMaxRetryRate(perSecond float64) Option {
+	return func(p *policy) {
+		p.maxRetryRate = perSecond
+	}
+}
+
+// retryRate spaces the retries of a Retrier evenly according to its maximum retry rate.
+type retryRate struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// reserve returns the delay after which a retry planned after delay may start. The returned delay is never shorter
+// than the given one. Every call reserves a slot, so that concurrent loops are spaced apart.
+func (r *retryRate) reserve(p *policy, delay time.Duration) time.Duration {
+	if p.maxRetryRate <= 0 {
+		return delay
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := p.now()
+	slot := now.Add(delay)
+	if slot.Before(r.next) {
+		slot = r.next
+	}
+	r.next = slot.Add(time.Duration(float64(time.Second) / p.maxRetryRate))
+	return slot.Sub(now)
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithMaxRetryRate(t *testing.T) {
+	t.Run("should space retries of concurrent loops", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithMaxRetryRate(50))
+		var mu sync.Mutex
+		var retries []time.Time
+		var wg sync.WaitGroup
+
+		// when
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = sut.Do(context.Background(), func(ctx context.Context) error {
+					if attempt, _ := AttemptFromContext(ctx); attempt.Number == 2 {
+						mu.Lock()
+						retries = append(retries, time.Now())
+						mu.Unlock()
+					}
+					return assert.AnError
+				})
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Len(t, retries, 3)
+		first, last := retries[0], retries[0]
+		for _, at := range retries {
+			if at.Before(first) {
+				first = at
+			}
+			if at.After(last) {
+				last = at
+			}
+		}
+		assert.GreaterOrEqual(t, last.Sub(first), 35*time.Millisecond)
+	})
+	t.Run("should keep longer delays", func(t *testing.T) {
+		// given
+		sut := New(WithMaxRetryRate(10))
+		p := sut.policy.Load()
+
+		// when
+		first := sut.rate.reserve(p, time.Second)
+		second := sut.rate.reserve(p, 0)
+
+		// then
+		assert.Equal(t, time.Second, first)
+		assert.InDelta(t, 1100*time.Millisecond, second, float64(10*time.Millisecond))
+	})
+	t.Run("should not delay without cap", func(t *testing.T) {
+		// given
+		sut := New()
+
+		// when
+		actual := sut.rate.reserve(sut.policy.Load(), time.Millisecond)
+
+		// then
+		assert.Equal(t, time.Millisecond, actual)
+	})
+}
@@ -0,0 +1,76 @@
+package retry
+
+import "sync"
+
+// Registry maintains named Retriers, e.g. one per operation, whose policies can be reloaded at runtime with
+// Registry.Apply or WatchFile. A Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	retriers map[string]*Retrier
+	base     map[string][]Option
+	configs  map[string]PolicyConfig
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		retriers: map[string]*Retrier{},
+		base:     map[string][]Option{},
+		configs:  map[string]PolicyConfig{},
+	}
+}
+
+// Register sets the base options of the named Retrier. The configuration applied with Apply is layered on top of
+// them. Register returns the Retrier, which is created if necessary.
+func (r *Registry) Register(name string, opts ...Option) *Retrier {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.base[name] = opts
+	retrier := r.getLocked(name)
+	retrier.policy.Store(r.policyLocked(name))
+	return retrier
+}
+
+// Get returns the named Retrier. Unknown names get a Retrier with the default policy and the applied
+// configuration, if any. The same name always yields the same Retrier, so it may be kept by the caller.
+func (r *Registry) Get(name string) *Retrier {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.getLocked(name)
+}
+
+// Apply replaces the configuration of all Retriers. Retriers missing in configs fall back to their base options.
+func (r *Registry) Apply(configs map[string]PolicyConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.configs = configs
+	for name, retrier := range r.retriers {
+		retrier.policy.Store(r.policyLocked(name))
+	}
+}
+
+func (r *Registry) getLocked(name string) *Retrier {
+	retrier, ok := r.retriers[name]
+	if !ok {
+		retrier = &Retrier{}
+		retrier.policy.Store(r.policyLocked(name))
+		r.retriers[name] = retrier
+	}
+	return retrier
+}
+
+func (r *Registry) policyLocked(name string) *policy {
+	p := defaultPolicy()
+	for _, opt := range r.base[name] {
+		opt(&p)
+	}
+	if config, ok := r.configs[name]; ok {
+		for _, opt := range config.Options() {
+			opt(&p)
+		}
+	}
+	return &p
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Registry(t *testing.T) {
+	t.Run("should return same retrier per name", func(t *testing.T) {
+		// given
+		sut := NewRegistry()
+
+		// when
+		first := sut.Get("backup")
+		again := sut.Get("backup")
+		other := sut.Get("restore")
+
+		// then
+		assert.Same(t, first, again)
+		assert.NotSame(t, first, other)
+	})
+	t.Run("should layer configuration on top of base options", func(t *testing.T) {
+		// given
+		sut := NewRegistry()
+		retrier := sut.Register("backup", WithBackoff(Constant(time.Second)), WithMaxTries(3))
+		maxTries := 7
+
+		// when
+		sut.Apply(map[string]PolicyConfig{"backup": {MaxTries: &maxTries}})
+
+		// then
+		assert.Same(t, retrier, sut.Get("backup"))
+		assert.Equal(t, 7, retrier.policy.Load().maxTries)
+		assert.Equal(t, time.Second, retrier.DelayFor(1))
+	})
+	t.Run("should fall back to base options when configuration is removed", func(t *testing.T) {
+		// given
+		sut := NewRegistry()
+		retrier := sut.Register("backup", WithMaxTries(3))
+		maxTries := 7
+		sut.Apply(map[string]PolicyConfig{"backup": {MaxTries: &maxTries}})
+
+		// when
+		sut.Apply(map[string]PolicyConfig{})
+
+		// then
+		assert.Equal(t, 3, retrier.policy.Load().maxTries)
+	})
+	t.Run("should configure retriers created later", func(t *testing.T) {
+		// given
+		sut := NewRegistry()
+		initialDelay := Duration(time.Minute)
+		sut.Apply(map[string]PolicyConfig{"backup": {InitialDelay: &initialDelay}})
+
+		// when
+		retrier := sut.Get("backup")
+
+		// then
+		assert.Equal(t, time.Minute, retrier.DelayFor(1))
+	})
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Outcome describes how a retry loop ended.
+type Outcome string
+
+const (
+	// OutcomeSucceeded means that the workload finally succeeded.
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeFailed means that the workload returned a non-retriable or aborting error.
+	OutcomeFailed Outcome = "failed"
+	// OutcomeExhausted means that the retry limits were reached.
+	OutcomeExhausted Outcome = "exhausted"
+	// OutcomeCanceled means that the context was done before the workload succeeded.
+	OutcomeCanceled Outcome = "canceled"
+	// OutcomeDisabled means that the workload failed once and was not retried as retries are disabled.
+	OutcomeDisabled Outcome = "disabled"
+	// OutcomeCoolingDown means that the workload was not executed as the Retrier cools down, see WithCooldown.
+	OutcomeCoolingDown Outcome = "cooldown"
+)
+
+// Report documents a complete retry loop. It is meant for audit logs of critical operations like backups and
+// restores and can be serialized with encoding/json.
+type Report struct {
+	// Outcome describes how the loop ended.
+	Outcome Outcome
+	// Start is the point in time the loop began.
+	Start time.Time
+	// Duration is the total time the loop took, including all delays.
+	Duration time.Duration
+	// Attempts lists all executions of the workload in order.
+	Attempts []AttemptRecord
+	// Err is the error returned by the loop, if any.
+	Err error
+}
+
+type reportJSON struct {
+	Outcome  Outcome         `json:"outcome"`
+	Start    time.Time       `json:"start"`
+	Duration string          `json:"duration"`
+	Attempts []AttemptRecord `json:"attempts"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the report with human-readable durations and the error as string.
+func (r Report) MarshalJSON() ([]byte, error) {
+	report := reportJSON{
+		Outcome:  r.Outcome,
+		Start:    r.Start,
+		Duration: r.Duration.String(),
+		Attempts: r.Attempts,
+	}
+	if r.Err != nil {
+		report.Error = r.Err.Error()
+	}
+
+	return json.Marshal(report)
+}
+
+// WriteJSON writes the report as JSON to w.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// DoWithReport works like Do but additionally returns a Report documenting every attempt. The report is returned
+// regardless of the outcome.
+func (r *Retrier) DoWithReport(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) (*Report, error) {
+	return r.do(ctx, fn, true, opts)
+}
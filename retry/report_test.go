@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retrier_DoWithReport(t *testing.T) {
+	t.Run("should report succeeded loop", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)))
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return assert.AnError
+			}
+			return nil
+		}
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, OutcomeSucceeded, report.Outcome)
+		require.Len(t, report.Attempts, 3)
+		assert.ErrorIs(t, report.Attempts[0].Err, assert.AnError)
+		assert.Equal(t, time.Millisecond, report.Attempts[0].Delay)
+		assert.NoError(t, report.Attempts[2].Err)
+		assert.GreaterOrEqual(t, report.Duration, 2*time.Millisecond)
+	})
+	t.Run("should report exhausted loop", func(t *testing.T) {
+		// given
+		sut := New(WithMaxTries(2), WithBackoff(Constant(time.Millisecond)))
+		fn := func(ctx context.Context) error {
+			return assert.AnError
+		}
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), fn)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, OutcomeExhausted, report.Outcome)
+		assert.Equal(t, err, report.Err)
+		assert.Len(t, report.Attempts, 2)
+	})
+	t.Run("should report failed loop", func(t *testing.T) {
+		// given
+		sut := New()
+		fn := func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		}
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), fn)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, OutcomeFailed, report.Outcome)
+		assert.Len(t, report.Attempts, 1)
+	})
+	t.Run("should report canceled loop", func(t *testing.T) {
+		// given
+		sut := New()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		report, err := sut.DoWithReport(ctx, func(ctx context.Context) error { return nil })
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, OutcomeCanceled, report.Outcome)
+		assert.Empty(t, report.Attempts)
+	})
+}
+
+func Test_Report_WriteJSON(t *testing.T) {
+	// given
+	start := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+	sut := &Report{
+		Outcome:  OutcomeExhausted,
+		Start:    start,
+		Duration: 2 * time.Second,
+		Attempts: []AttemptRecord{{Attempt: 1, Start: start, Duration: time.Second, Err: assert.AnError}},
+		Err:      assert.AnError,
+	}
+	buf := &bytes.Buffer{}
+
+	// when
+	err := sut.WriteJSON(buf)
+
+	// then
+	require.NoError(t, err)
+	var actual map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	assert.Equal(t, "exhausted", actual["outcome"])
+	assert.Equal(t, "2s", actual["duration"])
+	assert.Equal(t, assert.AnError.Error(), actual["error"])
+	assert.Len(t, actual["attempts"], 1)
+}
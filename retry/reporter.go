@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ErrorReporter forwards errors to an error tracker like Sentry. It is only called if a retry loop is exhausted, so
+// that transient failures resolved by a retry do not create noise while persistent ones are tracked.
+// Implementations must be safe for concurrent use and should return quickly as they are called synchronously at the
+// end of the loop.
+type ErrorReporter interface {
+	// ReportError is called with the context of the retry loop, the operation's name set with WithOperation and the
+	// resulting error, i.e. an ExhaustedError or a NoProgressError.
+	ReportError(ctx context.Context, operation string, err error)
+}
+
+// ErrorReporterFunc adapts an ordinary function to the ErrorReporter interface.
+type ErrorReporterFunc func(ctx context.Context, operation string, err error)
+
+// ReportError calls f(ctx, operation, err).
+func (f ErrorReporterFunc) ReportError(ctx context.Context, operation string, err error) {
+	f(ctx, operation, err)
+}
+
+type reporterHolder struct {
+	reporter ErrorReporter
+}
+
+var defaultReporter atomic.Pointer[reporterHolder]
+
+// SetDefaultErrorReporter sets the ErrorReporter used by all Retriers without a reporter configured with
+// WithErrorReporter. Passing nil disables reporting again.
+func SetDefaultErrorReporter(reporter ErrorReporter) {
+	defaultReporter.Store(&reporterHolder{reporter: reporter})
+}
+
+// DefaultErrorReporter returns the ErrorReporter set with SetDefaultErrorReporter or nil.
+func DefaultErrorReporter() ErrorReporter {
+	holder := defaultReporter.Load()
+	if holder == nil {
+		return nil
+	}
+	return holder.reporter
+}
+
+// WithErrorReporter sets the ErrorReporter receiving the error of every exhausted retry loop. It takes precedence
+// over the default reporter.
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(p *policy) {
+		p.reporter = reporter
+	}
+}
+
+// reportExhaustion passes the error of an exhausted retry loop to the configured or the default reporter.
+func (p *policy) reportExhaustion(ctx context.Context, outcome Outcome, err error) {
+	if outcome != OutcomeExhausted {
+		return
+	}
+
+	reporter := p.reporter
+	if reporter == nil {
+		reporter = DefaultErrorReporter()
+	}
+	if reporter != nil {
+		reporter.ReportError(ctx, p.operation, err)
+	}
+}
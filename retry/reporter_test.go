@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportedError struct {
+	operation string
+	err       error
+}
+
+func recordingReporter(reported *[]reportedError) ErrorReporter {
+	return ErrorReporterFunc(func(ctx context.Context, operation string, err error) {
+		*reported = append(*reported, reportedError{operation: operation, err: err})
+	})
+}
+
+func Test_WithErrorReporter(t *testing.T) {
+	t.Run("should report exhaustion", func(t *testing.T) {
+		// given
+		var reported []reportedError
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2), WithOperation("backup"), WithErrorReporter(recordingReporter(&reported)))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Len(t, reported, 1)
+		assert.Equal(t, "backup", reported[0].operation)
+		assert.Equal(t, err, reported[0].err)
+		var exhaustedErr *ExhaustedError
+		assert.ErrorAs(t, reported[0].err, &exhaustedErr)
+	})
+	t.Run("should not report transient failures", func(t *testing.T) {
+		// given
+		var reported []reportedError
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithErrorReporter(recordingReporter(&reported)))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, reported)
+	})
+	t.Run("should not report non-retriable errors", func(t *testing.T) {
+		// given
+		var reported []reportedError
+		sut := New(WithBackoff(Constant(0)), WithErrorReporter(recordingReporter(&reported)))
+
+		// when
+		_, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return Abort(assert.AnError)
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, reported)
+	})
+}
+
+func Test_SetDefaultErrorReporter(t *testing.T) {
+	t.Run("should use default reporter", func(t *testing.T) {
+		// given
+		var reported []reportedError
+		SetDefaultErrorReporter(recordingReporter(&reported))
+		defer SetDefaultErrorReporter(nil)
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1))
+
+		// when
+		_, _ = sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.Len(t, reported, 1)
+	})
+	t.Run("should prefer configured reporter", func(t *testing.T) {
+		// given
+		var defaultReported, reported []reportedError
+		SetDefaultErrorReporter(recordingReporter(&defaultReported))
+		defer SetDefaultErrorReporter(nil)
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1), WithErrorReporter(recordingReporter(&reported)))
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.Empty(t, defaultReported)
+		assert.Len(t, reported, 1)
+	})
+}
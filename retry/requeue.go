@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// RequeueTracker remembers the number of consecutive failures per resource and computes escalating requeue delays
+// from the policy of a Retrier. It replaces the rate limiter of the controller's workqueue, whose backoff is fixed
+// per controller, by the configured policy. Operators usually key it by types.NamespacedName:
+//
+//	tracker := retry.NewRequeueTracker[types.NamespacedName](retrier)
+//
+//	func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+//		if err := r.reconcile(ctx, req); err != nil {
+//			log.FromContext(ctx).Error(err, "reconciliation failed")
+//			return ctrl.Result{RequeueAfter: tracker.Failure(req.NamespacedName)}, nil
+//		}
+//		tracker.Success(req.NamespacedName)
+//		return ctrl.Result{}, nil
+//	}
+//
+// Returning no error together with RequeueAfter keeps the workqueue from applying its own backoff. A RequeueTracker
+// is safe for concurrent use.
+type RequeueTracker[K comparable] struct {
+	retrier *Retrier
+
+	mu       sync.Mutex
+	failures map[K]int
+}
+
+// NewRequeueTracker creates a RequeueTracker computing the delays with the backoff and jitter of r. Changes of the
+// policy with Retrier.Update apply to the next computed delay.
+func NewRequeueTracker[K comparable](r *Retrier) *RequeueTracker[K] {
+	return &RequeueTracker[K]{retrier: r, failures: map[K]int{}}
+}
+
+// Failure records another failure of the resource with the given key and returns the delay after which it should be
+// processed again. The delay after the first failure is the one after the first attempt of the policy.
+func (t *RequeueTracker[K]) Failure(key K) time.Duration {
+	t.mu.Lock()
+	t.failures[key]++
+	failures := t.failures[key]
+	t.mu.Unlock()
+
+	return t.retrier.DelayFor(failures)
+}
+
+// Success resets the failures of the resource with the given key. It should also be called once a resource was
+// deleted, so that the tracker does not grow unbounded.
+func (t *RequeueTracker[K]) Success(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, key)
+}
+
+// Failures returns the number of consecutive failures recorded for the resource with the given key.
+func (t *RequeueTracker[K]) Failures(key K) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.failures[key]
+}
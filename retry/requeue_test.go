@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type resourceKey struct {
+	Namespace string
+	Name      string
+}
+
+func Test_RequeueTracker(t *testing.T) {
+	t.Run("should escalate delays per resource", func(t *testing.T) {
+		// given
+		sut := NewRequeueTracker[resourceKey](New(WithInitialDelay(time.Second), WithBackoffFactor(2), WithMaxDelay(3*time.Second)))
+		ldap := resourceKey{Namespace: "ecosystem", Name: "ldap"}
+		cas := resourceKey{Namespace: "ecosystem", Name: "cas"}
+
+		// when
+		delays := []time.Duration{sut.Failure(ldap), sut.Failure(ldap), sut.Failure(ldap)}
+		other := sut.Failure(cas)
+
+		// then
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, delays)
+		assert.Equal(t, time.Second, other)
+		assert.Equal(t, 3, sut.Failures(ldap))
+		assert.Equal(t, 1, sut.Failures(cas))
+	})
+	t.Run("should reset on success", func(t *testing.T) {
+		// given
+		sut := NewRequeueTracker[resourceKey](New(WithInitialDelay(time.Second), WithBackoffFactor(2)))
+		key := resourceKey{Name: "ldap"}
+		sut.Failure(key)
+		sut.Failure(key)
+
+		// when
+		sut.Success(key)
+
+		// then
+		assert.Equal(t, 0, sut.Failures(key))
+		assert.Equal(t, time.Second, sut.Failure(key))
+	})
+	t.Run("should follow policy updates", func(t *testing.T) {
+		// given
+		retrier := New(WithBackoff(Constant(time.Second)))
+		sut := NewRequeueTracker[resourceKey](retrier)
+		key := resourceKey{Name: "ldap"}
+		sut.Failure(key)
+
+		// when
+		retrier.Update(WithBackoff(Constant(time.Minute)))
+
+		// then
+		assert.Equal(t, time.Minute, sut.Failure(key))
+	})
+}
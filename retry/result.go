@@ -0,0 +1,26 @@
+package retry
+
+// OnErrorWithResult works like OnError for workloads returning a value besides the error. The value of the last
+// attempt is returned, so callers do not need to capture it in a variable outside the workload.
+func OnErrorWithResult[T any](maxTries int, retriable func(error) bool, workload func() (T, error)) (T, error) {
+	var result T
+	err := OnError(maxTries, retriable, func() error {
+		var err error
+		result, err = workload()
+		return err
+	})
+	return result, err
+}
+
+// OnErrorWithResult2 works like OnErrorWithResult for workloads returning two values besides the error, like client
+// calls returning an object and the metadata of the response.
+func OnErrorWithResult2[A, B any](maxTries int, retriable func(error) bool, workload func() (A, B, error)) (A, B, error) {
+	var first A
+	var second B
+	err := OnError(maxTries, retriable, func() error {
+		var err error
+		first, second, err = workload()
+		return err
+	})
+	return first, second, err
+}
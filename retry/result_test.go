@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OnErrorWithResult(t *testing.T) {
+	t.Run("should return value of successful attempt", func(t *testing.T) {
+		// given
+		calls := 0
+
+		// when
+		result, err := OnErrorWithResult(3, AlwaysRetryFunc, func() (string, error) {
+			calls++
+			return "value", nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "value", result)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should return value and error of non-retriable attempt", func(t *testing.T) {
+		// when
+		result, err := OnErrorWithResult(3, TestableRetryFunc, func() (int, error) {
+			return 42, assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 42, result)
+	})
+}
+
+func Test_OnErrorWithResult2(t *testing.T) {
+	t.Run("should return values of successful attempt", func(t *testing.T) {
+		// when
+		object, metadata, err := OnErrorWithResult2(3, AlwaysRetryFunc, func() (string, map[string]string, error) {
+			return "object", map[string]string{"etag": "1"}, nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "object", object)
+		assert.Equal(t, map[string]string{"etag": "1"}, metadata)
+	})
+	t.Run("should return values and error of non-retriable attempt", func(t *testing.T) {
+		// when
+		object, metadata, err := OnErrorWithResult2(3, TestableRetryFunc, func() (*int, string, error) {
+			return nil, "partial", assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, object)
+		assert.Equal(t, "partial", metadata)
+	})
+}
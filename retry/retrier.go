@@ -0,0 +1,286 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ExhaustedError is returned when a workload still fails with a retriable error after the last permitted attempt.
+type ExhaustedError struct {
+	// Attempts contains the number of times the workload was executed.
+	Attempts int
+	// Err contains the error of the last attempt.
+	Err error
+	// Timeline contains the history of all attempts if it was recorded with WithTimeline.
+	Timeline *Timeline
+	// Stacks contains the stack traces captured with Trace if they were enabled with WithStackTraces.
+	Stacks []AttemptStack
+}
+
+// Error returns the error's string representation.
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("the maximum number of retries was reached: %s", e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Interface executes workloads with retries. It is implemented by Retrier and lets services inject the retry
+// behaviour, so that unit tests can replace it with the mock of the retrytest package.
+type Interface interface {
+	// Do executes fn until it succeeds or the retry loop ends. opts override the policy for this call.
+	Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error
+}
+
+var _ Interface = (*Retrier)(nil)
+
+// Retrier executes workloads repeatedly until they succeed, fail with a non-retriable error or the configured limits
+// are reached. A Retrier is safe for concurrent use.
+type Retrier struct {
+	policy   atomic.Pointer[policy]
+	kicks    kicker
+	health   health
+	cooldown cooldown
+	rate     retryRate
+}
+
+// New creates a new Retrier. Without any options, workloads are retried on every error with an exponential backoff
+// starting at 1.5 seconds until three minutes have passed.
+func New(opts ...Option) *Retrier {
+	p := defaultPolicy()
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	r := &Retrier{}
+	r.policy.Store(&p)
+	return r
+}
+
+// Update changes the policy of the Retrier at runtime by applying opts on top of the current policy, e.g. when a
+// config watcher tightens or loosens the retry behaviour. The new policy is swapped in atomically: running retry
+// loops finish with the policy they started with, all loops started afterward use the new one.
+func (r *Retrier) Update(opts ...Option) {
+	for {
+		current := r.policy.Load()
+		updated := *current
+		for _, opt := range opts {
+			opt(&updated)
+		}
+		if r.policy.CompareAndSwap(current, &updated) {
+			return
+		}
+	}
+}
+
+// DelayFor returns the delay the Retrier waits after the given attempt, including jitter. The first attempt is
+// numbered 1. It does not execute anything and lets users test invariants of their configured policies like caps
+// and jitter bounds.
+func (r *Retrier) DelayFor(attempt int) time.Duration {
+	return r.policy.Load().delay(attempt)
+}
+
+// Do executes fn until it succeeds, returns a non-retriable error, the retry limits are reached or ctx is done.
+// A non-retriable error is returned unchanged while hitting the limits results in an ExhaustedError. fn may stop
+// the loop early by returning an error wrapped with Abort. opts override the policy of the Retrier for this single
+// call, e.g. to apply a tighter limit, so that slight variations do not require another Retrier. They are applied
+// after the options attached with ContextWithPolicy. A call succeeding on the first attempt allocates once, for the
+// context of the attempt carrying AttemptFromContext. It cannot be pooled, as contexts derived from it are still
+// used after the attempt returned, e.g. by net/http canceling the context of a request once its body is closed.
+func (r *Retrier) Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	_, err := r.do(ctx, fn, false, opts)
+	return err
+}
+
+// do executes the retry loop and hands the resulting report to the auditor, if any. The report is only created if
+// it is requested, audited or the timeline is recorded anyway.
+func (r *Retrier) do(ctx context.Context, fn func(ctx context.Context) error, withReport bool, opts []Option) (*Report, error) {
+	p := r.effectivePolicy(ctx, opts)
+	auditor := p.auditor
+	if auditor == nil {
+		auditor = DefaultAuditor()
+	}
+	if !withReport && auditor == nil && !p.recordTimeline {
+		outcome, err := r.run(ctx, p, fn, nil)
+		r.health.observe(p, outcome)
+		r.cooldown.observe(p, outcome)
+		p.reportExhaustion(ctx, outcome, err)
+		return nil, err
+	}
+
+	timeline := &Timeline{Start: p.now()}
+	outcome, err := r.run(ctx, p, fn, timeline)
+	r.health.observe(p, outcome)
+	r.cooldown.observe(p, outcome)
+	p.reportExhaustion(ctx, outcome, err)
+	report := &Report{
+		Outcome:  outcome,
+		Start:    timeline.Start,
+		Duration: p.since(timeline.Start),
+		Attempts: timeline.Attempts,
+		Err:      err,
+	}
+	if auditor != nil {
+		auditor.Audit(ctx, p.operation, report)
+	}
+
+	return report, err
+}
+
+// run executes the retry loop with the given policy and records every attempt in timeline if it is not nil.
+func (r *Retrier) run(ctx context.Context, p *policy, fn func(ctx context.Context) error, timeline *Timeline) (Outcome, error) {
+	if err := r.cooldown.check(p); err != nil {
+		return OutcomeCoolingDown, err
+	}
+
+	start := p.now()
+	var tracker *progressTracker
+	if p.progressWindow > 0 {
+		tracker = &progressTracker{last: start}
+	}
+	if onProgress := p.progressCallback(tracker); onProgress != nil {
+		ctx = context.WithValue(ctx, progressKey{}, onProgress)
+	}
+
+	// watchedAttempts is only allocated for the watchdog so that loops without one do not allocate
+	var watchedAttempts *atomic.Int64
+	if p.watchdogThreshold > 0 {
+		watchedAttempts = &atomic.Int64{}
+		watchdogStart := time.Now()
+		watchdog := time.AfterFunc(p.watchdogThreshold, func() {
+			p.onStuck(elapsedSince(watchdogStart), int(watchedAttempts.Load()))
+		})
+		defer watchdog.Stop()
+	}
+
+	if p.initialWait > 0 && !p.sleep(ctx, p.initialWait, nil) {
+		return OutcomeCanceled, contextError(ctx)
+	}
+
+	var lastErr, firstErr error
+	var previousDelay time.Duration
+	var firstAttempt time.Time
+	var stacks []AttemptStack
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return OutcomeCanceled, canceledError(ctx, attempt-1, lastErr)
+		}
+
+		if watchedAttempts != nil {
+			watchedAttempts.Store(int64(attempt))
+		}
+		stats.attempts.Add(1)
+		attemptStart := p.now()
+		if attempt == 1 {
+			firstAttempt = attemptStart
+		}
+		lastErr = p.attempt(ctx, AttemptInfo{Number: attempt, FirstAttempt: firstAttempt}, fn)
+		if timeline != nil {
+			timeline.Attempts = append(timeline.Attempts, AttemptRecord{
+				Attempt:  attempt,
+				Start:    attemptStart,
+				Duration: p.since(attemptStart),
+				Err:      lastErr,
+			})
+		}
+		if lastErr == nil {
+			p.recovered(attempt, firstErr)
+			return OutcomeSucceeded, nil
+		}
+		if firstErr == nil {
+			firstErr = lastErr
+		}
+		if stack, ok := stackOf(lastErr); ok {
+			stacks = append(stacks, AttemptStack{Attempt: attempt, Stack: stack})
+		}
+		if IsAborted(lastErr) {
+			return OutcomeFailed, lastErr
+		}
+		decision := p.classifier.Classify(lastErr)
+		if !decision.Retry {
+			return OutcomeFailed, lastErr
+		}
+		if RetriesDisabled() {
+			return OutcomeDisabled, &DisabledError{Err: lastErr}
+		}
+		if tracker != nil && p.since(tracker.lastProgress()) > p.progressWindow {
+			stats.exhausted.Add(1)
+			return OutcomeExhausted, &NoProgressError{Window: p.progressWindow, Attempts: attempt, Err: lastErr}
+		}
+		if p.maxTries > 0 && attempt >= p.maxTries {
+			return OutcomeExhausted, p.exhausted(attempt, lastErr, timeline, stacks)
+		}
+		delay := decision.Delay
+		if delay <= 0 {
+			var scheduled bool
+			if delay, scheduled = p.nextDelay(attempt); !scheduled {
+				return OutcomeExhausted, p.exhausted(attempt, lastErr, timeline, stacks)
+			}
+		}
+		if p.monotonic {
+			delay = max(delay, previousDelay)
+			previousDelay = delay
+		}
+		delay = p.spaced(delay, attemptStart)
+		if p.limit > 0 && delay > p.limit-p.since(start) {
+			return OutcomeExhausted, p.exhausted(attempt, lastErr, timeline, stacks)
+		}
+		succeeded, unknownErr := p.resolveUnknownOutcome(ctx, attempt, lastErr)
+		if unknownErr != nil {
+			return OutcomeFailed, unknownErr
+		}
+		if succeeded {
+			p.recovered(attempt, firstErr)
+			return OutcomeSucceeded, nil
+		}
+		delay = r.rate.reserve(p, delay)
+		if timeline != nil {
+			timeline.Attempts[len(timeline.Attempts)-1].Delay = delay
+		}
+		p.logAttempt(ctx, attempt, lastErr, delay)
+		if p.onRetry != nil {
+			p.onRetry(ctx, attempt, lastErr, delay)
+		}
+		stats.retries.Add(1)
+		kicked := r.kicks.wait()
+		if !p.sleep(ctx, delay, kicked) || !r.awaitTrigger(ctx, p, kicked) {
+			return OutcomeCanceled, canceledError(ctx, attempt, lastErr)
+		}
+		// The wait may take considerably longer than planned, e.g. if the system was suspended in between.
+		if p.limit > 0 && p.since(start) > p.limit {
+			return OutcomeExhausted, p.exhausted(attempt, lastErr, timeline, stacks)
+		}
+	}
+}
+
+func (p *policy) exhausted(attempts int, lastErr error, timeline *Timeline, stacks []AttemptStack) error {
+	stats.exhausted.Add(1)
+	if timeline != nil && p.timelineWriter != nil {
+		// The timeline is only a debugging aid, failing to write it must not hide the actual error.
+		_ = timeline.WriteJSON(p.timelineWriter)
+	}
+
+	return &ExhaustedError{Attempts: attempts, Err: lastErr, Timeline: timeline, Stacks: stacks}
+}
+
+func canceledError(ctx context.Context, attempts int, lastErr error) error {
+	if lastErr == nil {
+		return contextError(ctx)
+	}
+	return fmt.Errorf("retry was canceled after %d attempt(s): %w: %w", attempts, contextError(ctx), lastErr)
+}
+
+// contextError returns the error of ctx. If ctx was canceled with a cause, e.g. by context.WithCancelCause, the
+// error also wraps the cause, so that callers can tell a shutdown from a deadline or a cancellation by the user.
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	cause := context.Cause(ctx)
+	if cause == nil || cause == err {
+		return err
+	}
+	return fmt.Errorf("%w: %w", err, cause)
+}
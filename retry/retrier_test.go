@@ -0,0 +1,320 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retrier_Do(t *testing.T) {
+	t.Run("should succeed", func(t *testing.T) {
+		// given
+		sut := New()
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should retry once and succeed", func(t *testing.T) {
+		// given
+		sut := New()
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return assert.AnError
+			}
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("should fail after max tries", func(t *testing.T) {
+		// given
+		sut := New(WithMaxTries(1))
+		fn := func(ctx context.Context) error {
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, 1, exhaustedErr.Attempts)
+		assert.ErrorContains(t, err, "the maximum number of retries was reached")
+	})
+	t.Run("should fail if backoff exceeds limit", func(t *testing.T) {
+		// given
+		sut := New(WithLimit(time.Second))
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should return non-retriable error immediately", func(t *testing.T) {
+		// given
+		sut := New(WithRetriable(TestableRetryFunc))
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.Error(t, err)
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should stop when context is canceled", func(t *testing.T) {
+		// given
+		sut := New()
+		ctx, cancel := context.WithCancel(context.Background())
+		fn := func(ctx context.Context) error {
+			cancel()
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(ctx, fn)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("should not execute workload with canceled context", func(t *testing.T) {
+		// given
+		sut := New()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		fn := func(ctx context.Context) error {
+			calls++
+			return nil
+		}
+
+		// when
+		err := sut.Do(ctx, fn)
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func Test_Retrier_DelayFor(t *testing.T) {
+	t.Run("should return delay of backoff", func(t *testing.T) {
+		// given
+		sut := New(WithInitialDelay(time.Second), WithBackoffFactor(2), WithMaxDelay(5*time.Second))
+
+		// then
+		assert.Equal(t, time.Second, sut.DelayFor(1))
+		assert.Equal(t, 4*time.Second, sut.DelayFor(3))
+		assert.Equal(t, 5*time.Second, sut.DelayFor(100))
+	})
+	t.Run("should stay within jitter bounds", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Second)), WithJitterPercent(10))
+
+		for attempt := 1; attempt <= 1000; attempt++ {
+			// when
+			delay := sut.DelayFor(attempt)
+
+			// then
+			assert.GreaterOrEqual(t, delay, 900*time.Millisecond)
+			assert.LessOrEqual(t, delay, 1100*time.Millisecond)
+		}
+	})
+}
+
+func Test_Retrier_Update(t *testing.T) {
+	t.Run("should apply options on top of current policy", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(5))
+		calls := 0
+
+		// when
+		sut.Update(WithMaxTries(2))
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, time.Millisecond, sut.DelayFor(1))
+	})
+	t.Run("should not affect running loops", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(3))
+		calls := 0
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			sut.Update(WithMaxTries(1))
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(1))
+		wg := &sync.WaitGroup{}
+
+		// when
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				sut.Update(WithMaxTries(2))
+			}()
+			go func() {
+				defer wg.Done()
+				_ = sut.Do(context.Background(), func(ctx context.Context) error { return nil })
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, 2, sut.policy.Load().maxTries)
+	})
+}
+
+func Test_Retrier_Do_contextCause(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+
+	t.Run("should include cause of cancellation", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancelCause(context.Background())
+		sut := New(WithBackoff(Constant(0)))
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			cancel(errShutdown)
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errShutdown)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("should include cause of deadline", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithTimeoutCause(context.Background(), time.Millisecond, errShutdown)
+		defer cancel()
+		sut := New(WithBackoff(Constant(time.Hour)), WithLimit(0))
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, errShutdown)
+	})
+	t.Run("should return plain context error without cause", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sut := New()
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func Test_Retrier_Do_allocations(t *testing.T) {
+	// given
+	sut := New()
+	ctx := context.Background()
+	succeed := func(context.Context) error { return nil }
+
+	// when
+	actual := testing.AllocsPerRun(100, func() {
+		_ = sut.Do(ctx, succeed)
+	})
+
+	// then
+	assert.Equal(t, 1.0, actual, "only the context of the attempt may be allocated")
+}
+
+func Benchmark_Retrier_Do(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("succeeding", func(b *testing.B) {
+		sut := New()
+		succeed := func(context.Context) error { return nil }
+		b.ReportAllocs()
+		for range b.N {
+			_ = sut.Do(ctx, succeed)
+		}
+	})
+	b.Run("retrying once", func(b *testing.B) {
+		sut := New(WithBackoff(Constant(0)))
+		failed := false
+		failOnce := func(context.Context) error {
+			failed = !failed
+			if failed {
+				return assert.AnError
+			}
+			return nil
+		}
+		b.ReportAllocs()
+		for range b.N {
+			_ = sut.Do(ctx, failOnce)
+		}
+	})
+	b.Run("with per-call options", func(b *testing.B) {
+		sut := New()
+		succeed := func(context.Context) error { return nil }
+		opt := WithMaxTries(3)
+		b.ReportAllocs()
+		for range b.N {
+			_ = sut.Do(ctx, succeed, opt)
+		}
+	})
+}
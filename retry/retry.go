@@ -1,17 +1,15 @@
 package retry
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"time"
-
-	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/util/retry"
 )
 
-// TestableRetryFunc returns true if the returned error is a testableRetrierError and indicates that an action should be tried until the retrier hits its limit.
+// TestableRetryFunc returns true if the returned error is or wraps a testableRetrierError and indicates that an action should be tried until the retrier hits its limit.
 var TestableRetryFunc = func(err error) bool {
-	_, ok := err.(*TestableRetrierError)
-	return ok
+	var retrierErr *TestableRetrierError
+	return errors.As(err, &retrierErr)
 }
 
 // TestableRetrierError marks errors that indicate that a previously executed action should be retried with again. It must wrap an existing error.
@@ -24,46 +22,158 @@ func (tre *TestableRetrierError) Error() string {
 	return tre.Err.Error()
 }
 
+// AbortError marks errors that stop a retry loop immediately, regardless of whether the wrapped error would be
+// considered retriable. Please see Abort.
+type AbortError struct {
+	Err error
+}
+
+// Error returns the error's string representation.
+func (ae *AbortError) Error() string {
+	return ae.Err.Error()
+}
+
+// Unwrap returns the error that caused the abort.
+func (ae *AbortError) Unwrap() error {
+	return ae.Err
+}
+
+// Abort wraps err so that the retry loop executing the workload returns it without any further attempt. The
+// returned error unwraps to err, so errors.Is and errors.As work as before. Abort returns nil if err is nil.
+func Abort(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &AbortError{Err: err}
+}
+
+// IsAborted returns true if err is or wraps an error created by Abort.
+func IsAborted(err error) bool {
+	var abortErr *AbortError
+	return errors.As(err, &abortErr)
+}
+
 // AlwaysRetryFunc returns always true and thus indicates that always should be tried until the retrier hits its limit.
 var AlwaysRetryFunc = func(err error) bool {
 	return true
 }
 
+// ContainsRetryable returns a function that reports whether retriable is true for the given error or any error
+// wrapped by it. Both errors wrapped with fmt.Errorf("%w") and aggregates created by errors.Join are inspected, so
+// that a retriable cause buried inside an aggregate still triggers a retry.
+func ContainsRetryable(retriable func(error) bool) func(error) bool {
+	return func(err error) bool {
+		return containsRetryable(err, retriable)
+	}
+}
+
+func containsRetryable(err error, retriable func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if retriable(err) {
+		return true
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return containsRetryable(wrapper.Unwrap(), retriable)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range wrapper.Unwrap() {
+			if containsRetryable(wrapped, retriable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // OnError provides a K8s-way "retrier" mechanism. The value from retriable is used to indicate if workload should
 // retried another time. Please see AlwaysRetryFunc() if a workload should always retried until a fixed threshold is
 // reached.
 func OnError(maxTries int, retriable func(error) bool, workload func() error) error {
+	_, err := onError(maxTries, 3*time.Minute, retriable, workload)
+	return err
+}
+
+// OnErrorWithAttempts works like OnError and also returns how often workload was executed, e.g. to log that it
+// succeeded after a number of attempts.
+func OnErrorWithAttempts(maxTries int, retriable func(error) bool, workload func() error) (int, error) {
 	return onError(maxTries, 3*time.Minute, retriable, workload)
 }
 
-// OnErrorWithLimit provides a K8s-way "retrier" mechanism with a time limit as option.
+// OnErrorWithLimit provides a K8s-way "retrier" mechanism with a time limit as option. The limit is used as cap of
+// a wait.Backoff: after every failed attempt, the loop sleeps for the current delay, which starts at 1.5 seconds and
+// grows by the factor 1.5. Once the next delay would exceed the limit, the loop stops after this sleep without
+// another attempt. A failing workload therefore blocks for at least 1.5 seconds and up to about three times the
+// limit, e.g. it runs twice within 3.75 seconds for a limit of 3 seconds. Please see OnErrorWithInterval to control
+// the delays and to bound the total duration.
 func OnErrorWithLimit(limit time.Duration, retriable func(error) bool, workload func() error) error {
+	_, err := OnErrorWithLimitAndAttempts(limit, retriable, workload)
+	return err
+}
+
+// OnErrorWithLimitAndAttempts works like OnErrorWithLimit and also returns how often workload was executed.
+func OnErrorWithLimitAndAttempts(limit time.Duration, retriable func(error) bool, workload func() error) (int, error) {
 	// Use a high integer here to avoid limit the cap with the steps.
 	return onError(9999999, limit, retriable, workload)
 }
 
-func onError(maxTries int, limit time.Duration, retriable func(error) bool, workload func() error) error {
-	err := retry.OnError(wait.Backoff{
-		Duration: 1500 * time.Millisecond,
-		Factor:   1.5,
-		Jitter:   0,
-		Steps:    maxTries,
-		Cap:      limit,
-	}, retriable, workload)
+// OnErrorWithInterval retries workload as long as retriable reports its error as retriable, waiting for the delays
+// of backoff in between, e.g. Constant(5*time.Second) for a fixed interval. In contrast to OnErrorWithLimit, limit
+// bounds the total duration: no further attempt is started if its preceding delay would exceed the limit. A limit
+// of zero or less removes it, so the workload is retried until it succeeds.
+func OnErrorWithInterval(limit time.Duration, backoff Backoff, retriable func(error) bool, workload func() error) error {
+	retrier := New(
+		WithLimit(limit),
+		WithBackoff(backoff),
+		WithRetriable(retriable),
+	)
+	return retrier.Do(context.Background(), func(context.Context) error {
+		return workload()
+	})
+}
+
+var legacyBackoff = Exponential{Initial: 1500 * time.Millisecond, Factor: 1.5}
 
-	if err != nil && retriable(err) {
-		return fmt.Errorf("the maximum number of retries was reached: %w", err)
+func onError(maxTries int, limit time.Duration, retriable func(error) bool, workload func() error) (int, error) {
+	attempts, idleDelay := legacyAttempts(maxTries, limit)
+	if attempts == 0 {
+		return 0, nil
 	}
-	return err
+
+	retrier := New(
+		WithMaxTries(attempts),
+		WithLimit(0),
+		WithBackoff(legacyBackoff),
+		WithRetriable(retriable),
+	)
+	executed := 0
+	err := retrier.Do(context.Background(), func(context.Context) error {
+		executed++
+		return workload()
+	})
+
+	var exhaustedErr *ExhaustedError
+	if idleDelay > 0 && errors.As(err, &exhaustedErr) {
+		// wait.ExponentialBackoff sleeps before it notices that the cap was exceeded.
+		time.Sleep(idleDelay)
+	}
+	return executed, err
 }
 
-// OnConflict provides a K8s-way "retrier" mechanism to avoid conflicts on resource updates.
-func OnConflict(fn func() error) error {
-	return retry.RetryOnConflict(wait.Backoff{
-		Duration: 1500 * time.Millisecond,
-		Factor:   1.5,
-		Jitter:   0,
-		Steps:    9999,
-		Cap:      30 * time.Second,
-	}, fn)
+// legacyAttempts returns how often wait.ExponentialBackoff evaluates a workload with the given number of tries and
+// limit as cap. Once the next delay would exceed the cap, it sleeps for the current delay and stops without another
+// attempt. This delay is returned as idleDelay, which is zero if the tries are used up first.
+func legacyAttempts(maxTries int, limit time.Duration) (attempts int, idleDelay time.Duration) {
+	delay := legacyBackoff.Initial
+	for attempt := 1; attempt < maxTries; attempt++ {
+		next := time.Duration(float64(delay) * legacyBackoff.Factor)
+		if next > limit {
+			return attempt, delay
+		}
+		delay = next
+	}
+
+	return max(maxTries, 0), 0
 }
@@ -1,9 +1,8 @@
 package retry
 
 import (
+	"errors"
 	"fmt"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 	"time"
 
@@ -65,7 +64,9 @@ func Test_OnErrorWithLimit(t *testing.T) {
 	t.Run("should fail", func(t *testing.T) {
 		// given
 		limit := 3 * time.Second
+		calls := 0
 		fn := func() error {
+			calls++
 			println(fmt.Sprintf("Current time: %s", time.Now()))
 			return assert.AnError
 		}
@@ -80,41 +81,135 @@ func Test_OnErrorWithLimit(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.Greater(t, timeDiff, limit)
+		assert.Equal(t, 2, calls, "attempts like wait.ExponentialBackoff with the limit as cap")
 	})
 }
 
-func Test_OnConflict(t *testing.T) {
-	t.Run("should retry once and succeed", func(t *testing.T) {
+func Test_OnErrorWithAttempts(t *testing.T) {
+	t.Run("should count attempts until success", func(t *testing.T) {
 		// given
-		retryCount := 0
+		calls := 0
 		fn := func() error {
-			retryCount++
-			if retryCount == 1 {
-				return &errors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonConflict}}
+			calls++
+			if calls < 2 {
+				return assert.AnError
 			}
 			return nil
 		}
 
 		// when
-		err := OnConflict(fn)
+		attempts, err := OnErrorWithAttempts(3, AlwaysRetryFunc, fn)
 
 		// then
 		require.NoError(t, err)
-		assert.Equal(t, 2, retryCount)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, calls, attempts)
 	})
-	t.Run("should fail", func(t *testing.T) {
+	t.Run("should count all tries on persistent failure", func(t *testing.T) {
 		// given
+		calls := 0
 		fn := func() error {
-			println(fmt.Sprintf("Current time: %s", time.Now()))
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		attempts, err := OnErrorWithAttempts(2, AlwaysRetryFunc, fn)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, calls, attempts)
+	})
+	t.Run("should count no attempts without tries", func(t *testing.T) {
+		// when
+		attempts, err := OnErrorWithAttempts(0, AlwaysRetryFunc, func() error { return assert.AnError })
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, attempts)
+	})
+}
+
+func Test_OnErrorWithLimitAndAttempts(t *testing.T) {
+	t.Run("should count non-retriable failure", func(t *testing.T) {
+		// when
+		attempts, err := OnErrorWithLimitAndAttempts(time.Second, TestableRetryFunc, func() error { return assert.AnError })
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, attempts)
+	})
+	t.Run("should not count another attempt once the limit is exceeded", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		attempts, err := OnErrorWithLimitAndAttempts(2*time.Millisecond, AlwaysRetryFunc, fn)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, calls, attempts)
+	})
+}
+
+func Test_OnErrorWithInterval(t *testing.T) {
+	t.Run("should retry with fixed interval until limit", func(t *testing.T) {
+		// given
+		var starts []time.Time
+		fn := func() error {
+			starts = append(starts, time.Now())
 			return assert.AnError
 		}
 
 		// when
-		err := OnConflict(fn)
+		err := OnErrorWithInterval(250*time.Millisecond, Constant(100*time.Millisecond), AlwaysRetryFunc, fn)
 
 		// then
 		require.Error(t, err)
 		assert.ErrorIs(t, err, assert.AnError)
+		require.Len(t, starts, 3)
+		for i := 1; i < len(starts); i++ {
+			assert.GreaterOrEqual(t, starts[i].Sub(starts[i-1]), 100*time.Millisecond)
+		}
+	})
+	t.Run("should return non-retriable error immediately", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			return assert.AnError
+		}
+
+		// when
+		err := OnErrorWithInterval(time.Minute, Constant(time.Minute), TestableRetryFunc, fn)
+
+		// then
+		assert.Same(t, assert.AnError, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should succeed after retries", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			if calls < 3 {
+				return assert.AnError
+			}
+			return nil
+		}
+
+		// when
+		err := OnErrorWithInterval(0, Constant(time.Millisecond), AlwaysRetryFunc, fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
 	})
 }
 
@@ -131,4 +226,82 @@ func Test_TestableRetryFunc(t *testing.T) {
 	retrierErr := new(TestableRetrierError)
 	retrierErr.Err = assert.AnError
 	assert.True(t, TestableRetryFunc(retrierErr))
+	assert.True(t, TestableRetryFunc(fmt.Errorf("wrapped: %w", retrierErr)))
+	assert.True(t, TestableRetryFunc(errors.Join(assert.AnError, retrierErr)))
+}
+
+func Test_ContainsRetryable(t *testing.T) {
+	isAnError := func(err error) bool {
+		return err == assert.AnError
+	}
+	sut := ContainsRetryable(isAnError)
+
+	assert.False(t, sut(nil))
+	assert.False(t, sut(errors.New("other")))
+	assert.True(t, sut(assert.AnError))
+	assert.True(t, sut(fmt.Errorf("wrapped: %w", assert.AnError)))
+	assert.True(t, sut(errors.Join(errors.New("other"), fmt.Errorf("wrapped: %w", assert.AnError))))
+	assert.True(t, sut(fmt.Errorf("wrapped: %w", errors.Join(errors.New("other"), assert.AnError))))
+	assert.False(t, sut(errors.Join(errors.New("other"), errors.New("another"))))
+}
+
+func Test_legacyAttempts(t *testing.T) {
+	tests := []struct {
+		maxTries          int
+		limit             time.Duration
+		expectedAttempts  int
+		expectedIdleDelay time.Duration
+	}{
+		{0, 3 * time.Minute, 0, 0},
+		{1, 3 * time.Minute, 1, 0},
+		{2, 3 * time.Minute, 2, 0},
+		{9999999, 2 * time.Millisecond, 1, 1500 * time.Millisecond},
+		{9999999, 3 * time.Second, 2, 2250 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		attempts, idleDelay := legacyAttempts(tt.maxTries, tt.limit)
+		assert.Equal(t, tt.expectedAttempts, attempts, "attempts for %d tries within %s", tt.maxTries, tt.limit)
+		assert.Equal(t, tt.expectedIdleDelay, idleDelay, "idle delay for %d tries within %s", tt.maxTries, tt.limit)
+	}
+}
+
+func Test_Abort(t *testing.T) {
+	t.Run("should return nil for nil", func(t *testing.T) {
+		assert.NoError(t, Abort(nil))
+	})
+	t.Run("should wrap cause", func(t *testing.T) {
+		// when
+		err := Abort(assert.AnError)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, assert.AnError.Error(), err.Error())
+		assert.True(t, IsAborted(err))
+		assert.True(t, IsAborted(fmt.Errorf("wrapped: %w", err)))
+		assert.False(t, IsAborted(assert.AnError))
+	})
+	t.Run("should stop retry loop", func(t *testing.T) {
+		// given
+		calls := 0
+		fn := func() error {
+			calls++
+			return Abort(assert.AnError)
+		}
+
+		// when
+		err := OnError(5, AlwaysRetryFunc, fn)
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, IsAborted(err))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func Benchmark_OnError(b *testing.B) {
+	succeed := func() error { return nil }
+	b.ReportAllocs()
+	for range b.N {
+		_ = OnError(3, AlwaysRetryFunc, succeed)
+	}
 }
@@ -0,0 +1,23 @@
+// Package retryexpvar publishes the counters of retry.GlobalStats as the expvar variable "retry". It is imported for
+// its side effect only:
+//
+//	import _ "github.com/cloudogu/retry-lib/retry/retryexpvar"
+//
+// The counters are then served as JSON by the handler of the expvar package at /debug/vars, e.g.
+// {"attempts": 12, "retries": 4, "exhausted": 1}.
+package retryexpvar
+
+import (
+	"expvar"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// VarName is the name the counters are published with.
+const VarName = "retry"
+
+func init() {
+	expvar.Publish(VarName, expvar.Func(func() any {
+		return retry.GlobalStats()
+	}))
+}
@@ -0,0 +1,32 @@
+package retryexpvar
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_init(t *testing.T) {
+	// given
+	sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(2))
+	_ = sut.Do(context.Background(), func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	// when
+	published := expvar.Get(VarName)
+
+	// then
+	require.NotNil(t, published)
+	var stats retry.Stats
+	require.NoError(t, json.Unmarshal([]byte(published.String()), &stats))
+	assert.Equal(t, int64(2), stats.Attempts)
+	assert.Equal(t, int64(1), stats.Retries)
+	assert.Equal(t, int64(1), stats.Exhausted)
+}
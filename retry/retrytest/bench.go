@@ -0,0 +1,48 @@
+package retrytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// allocRuns is the number of calls the allocations are averaged over.
+const allocRuns = 100
+
+// AllocsPerDo returns the average number of heap allocations of a call of r.Do executing fn, including the ones of
+// fn itself. Like testing.AllocsPerRun, it must not be called in parallel tests.
+func AllocsPerDo(r retry.Interface, fn func(ctx context.Context) error) float64 {
+	ctx := context.Background()
+	return testing.AllocsPerRun(allocRuns, func() {
+		_ = r.Do(ctx, fn)
+	})
+}
+
+// AssertAllocsPerDo fails the test if a call of r.Do executing fn allocates more than budget times on average. It
+// keeps the allocation budget of hot paths from growing unnoticed in CI.
+func AssertAllocsPerDo(t TestingT, budget float64, r retry.Interface, fn func(ctx context.Context) error) bool {
+	t.Helper()
+
+	if actual := AllocsPerDo(r, fn); actual > budget {
+		t.Errorf("expected at most %v allocation(s) per call but got %v", budget, actual)
+		return false
+	}
+	return true
+}
+
+// BenchmarkDo measures calls of r.Do executing fn and reports their allocations:
+//
+//	func BenchmarkSync(b *testing.B) {
+//		retrytest.BenchmarkDo(b, retrier, syncOnce)
+//	}
+func BenchmarkDo(b *testing.B, r retry.Interface, fn func(ctx context.Context) error) {
+	b.Helper()
+	b.ReportAllocs()
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for range b.N {
+		_ = r.Do(ctx, fn)
+	}
+}
@@ -0,0 +1,49 @@
+package retrytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_AssertAllocsPerDo(t *testing.T) {
+	succeed := func(context.Context) error { return nil }
+
+	t.Run("should pass within budget", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		actual := AssertAllocsPerDo(fake, 1, retry.New(), succeed)
+
+		// then
+		assert.True(t, actual)
+		assert.Empty(t, fake.errors)
+	})
+	t.Run("should fail above budget", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		actual := AssertAllocsPerDo(fake, 0, retry.New(), func(context.Context) error {
+			sink = make([]byte, 64)
+			return nil
+		})
+
+		// then
+		assert.False(t, actual)
+		require.Len(t, fake.errors, 1)
+		assert.Contains(t, fake.errors[0], "expected at most 0 allocation(s) per call")
+	})
+}
+
+// sink keeps allocations in tests from being optimized away.
+var sink []byte
+
+func Benchmark_BenchmarkDo(b *testing.B) {
+	BenchmarkDo(b, retry.New(), func(context.Context) error { return nil })
+}
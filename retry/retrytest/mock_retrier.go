@@ -0,0 +1,99 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package retrytest
+
+import (
+	context "context"
+
+	retry "github.com/cloudogu/retry-lib/retry"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRetrier is an autogenerated mock type for the Interface type
+type MockRetrier struct {
+	mock.Mock
+}
+
+type MockRetrier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRetrier) EXPECT() *MockRetrier_Expecter {
+	return &MockRetrier_Expecter{mock: &_m.Mock}
+}
+
+// Do provides a mock function with given fields: ctx, fn, opts
+func (_m *MockRetrier) Do(ctx context.Context, fn func(context.Context) error, opts ...retry.Option) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, fn)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Do")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error, ...retry.Option) error); ok {
+		r0 = rf(ctx, fn, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRetrier_Do_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Do'
+type MockRetrier_Do_Call struct {
+	*mock.Call
+}
+
+// Do is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(context.Context) error
+//   - opts ...retry.Option
+func (_e *MockRetrier_Expecter) Do(ctx interface{}, fn interface{}, opts ...interface{}) *MockRetrier_Do_Call {
+	return &MockRetrier_Do_Call{Call: _e.mock.On("Do",
+		append([]interface{}{ctx, fn}, opts...)...)}
+}
+
+func (_c *MockRetrier_Do_Call) Run(run func(ctx context.Context, fn func(context.Context) error, opts ...retry.Option)) *MockRetrier_Do_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]retry.Option, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(retry.Option)
+			}
+		}
+		run(args[0].(context.Context), args[1].(func(context.Context) error), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockRetrier_Do_Call) Return(_a0 error) *MockRetrier_Do_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRetrier_Do_Call) RunAndReturn(run func(context.Context, func(context.Context) error, ...retry.Option) error) *MockRetrier_Do_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRetrier creates a new instance of MockRetrier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRetrier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRetrier {
+	mock := &MockRetrier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
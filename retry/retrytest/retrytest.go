@@ -0,0 +1,120 @@
+// Package retrytest provides helpers asserting how often code under test retries.
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// TestingT is the subset of testing.T used by the assertion helpers.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Recorder creates instrumented Retriers and records all their attempts. A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	reports []*retry.Report
+}
+
+// Retrier returns a Retrier recording its attempts in the Recorder. Unless overridden by opts, the Retrier does not
+// wait between attempts so that tests run fast.
+func (r *Recorder) Retrier(opts ...retry.Option) *retry.Retrier {
+	defaults := []retry.Option{retry.WithBackoff(retry.Constant(0))}
+	return retry.New(append(append(defaults, opts...), retry.WithAuditor(r))...)
+}
+
+// Audit implements retry.Auditor.
+func (r *Recorder) Audit(_ context.Context, _ string, report *retry.Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports = append(r.reports, report)
+}
+
+// Attempts returns the number of attempts of all retry loops executed so far.
+func (r *Recorder) Attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts := 0
+	for _, report := range r.reports {
+		attempts += len(report.Attempts)
+	}
+	return attempts
+}
+
+// RetriedErrors returns the errors of all attempts that were followed by another attempt.
+func (r *Recorder) RetriedErrors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var retried []error
+	for _, report := range r.reports {
+		for i := 0; i < len(report.Attempts)-1; i++ {
+			retried = append(retried, report.Attempts[i].Err)
+		}
+	}
+	return retried
+}
+
+// AssertAttempts runs fn with an instrumented Retrier and fails the test unless the workloads were executed exactly
+// expected times in total. fn may use the Retrier for any number of retry loops.
+func AssertAttempts(t TestingT, expected int, fn func(r *retry.Retrier), opts ...retry.Option) bool {
+	t.Helper()
+
+	recorder := &Recorder{}
+	fn(recorder.Retrier(opts...))
+	if actual := recorder.Attempts(); actual != expected {
+		t.Errorf("expected %d attempt(s) but got %d", expected, actual)
+		return false
+	}
+	return true
+}
+
+// AssertRetriedErrors runs fn with an instrumented Retrier and fails the test unless the retried errors match the
+// expected ones in order. An error matches if errors.Is reports it to be the expected one.
+func AssertRetriedErrors(t TestingT, expected []error, fn func(r *retry.Retrier), opts ...retry.Option) bool {
+	t.Helper()
+
+	recorder := &Recorder{}
+	fn(recorder.Retrier(opts...))
+	actual := recorder.RetriedErrors()
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d retried error(s) but got %d: %v", len(expected), len(actual), actual)
+		return false
+	}
+	for i := range expected {
+		if !errors.Is(actual[i], expected[i]) {
+			t.Errorf("expected retried error %d to be %q but got %q", i+1, expected[i], actual[i])
+			return false
+		}
+	}
+	return true
+}
+
+// Eventually retries assertion according to the policy of r until it returns nil and fails the test otherwise. In
+// contrast to assert.Eventually, the polling follows the backoff and limits of r, and the failure message contains
+// the number of attempts, the elapsed time and the error of the last attempt, so that it is evident why the
+// assertion did not pass.
+func Eventually(t TestingT, r *retry.Retrier, assertion func() error) bool {
+	t.Helper()
+
+	report, err := r.DoWithReport(context.Background(), func(context.Context) error {
+		return assertion()
+	})
+	if err == nil {
+		return true
+	}
+
+	lastErr := err
+	if len(report.Attempts) > 0 {
+		lastErr = report.Attempts[len(report.Attempts)-1].Err
+	}
+	t.Errorf("assertion not satisfied after %d attempt(s) in %s (%s): %v", len(report.Attempts), report.Duration, report.Outcome, lastErr)
+	return false
+}
@@ -0,0 +1,181 @@
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+var errFlaky = errors.New("flaky")
+
+func failTwice(r *retry.Retrier) {
+	calls := 0
+	_ = r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("call %d: %w", calls, errFlaky)
+		}
+		return nil
+	})
+}
+
+func Test_AssertAttempts(t *testing.T) {
+	t.Run("should pass on matching attempts", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertAttempts(fake, 3, failTwice)
+
+		// then
+		assert.True(t, ok)
+		assert.Empty(t, fake.errors)
+	})
+	t.Run("should fail on other attempts", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertAttempts(fake, 2, failTwice)
+
+		// then
+		assert.False(t, ok)
+		assert.Equal(t, []string{"expected 2 attempt(s) but got 3"}, fake.errors)
+	})
+	t.Run("should apply options", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertAttempts(fake, 1, failTwice, retry.WithMaxTries(1))
+
+		// then
+		assert.True(t, ok)
+	})
+}
+
+func Test_AssertRetriedErrors(t *testing.T) {
+	t.Run("should pass on matching errors", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertRetriedErrors(fake, []error{errFlaky, errFlaky}, failTwice)
+
+		// then
+		assert.True(t, ok)
+		assert.Empty(t, fake.errors)
+	})
+	t.Run("should fail on other errors", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertRetriedErrors(fake, []error{errFlaky, assert.AnError}, failTwice)
+
+		// then
+		assert.False(t, ok)
+		assert.Len(t, fake.errors, 1)
+		assert.Contains(t, fake.errors[0], "expected retried error 2")
+	})
+	t.Run("should fail on other count", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+
+		// when
+		ok := AssertRetriedErrors(fake, []error{errFlaky}, failTwice)
+
+		// then
+		assert.False(t, ok)
+		assert.Contains(t, fake.errors[0], "expected 1 retried error(s) but got 2")
+	})
+}
+
+func Test_Recorder(t *testing.T) {
+	// given
+	sut := &Recorder{}
+	r := sut.Retrier(retry.WithMaxTries(2))
+
+	// when
+	failTwice(r)
+	failTwice(r)
+
+	// then
+	assert.Equal(t, 4, sut.Attempts())
+	assert.Len(t, sut.RetriedErrors(), 2)
+}
+
+func Test_MockRetrier(t *testing.T) {
+	// given
+	var sut retry.Interface = NewMockRetrier(t)
+	sut.(*MockRetrier).EXPECT().Do(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error, _ ...retry.Option) error {
+			return fn(ctx)
+		})
+	called := false
+
+	// when
+	err := sut.Do(context.Background(), func(ctx context.Context) error {
+		called = true
+		return assert.AnError
+	})
+
+	// then
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.True(t, called)
+}
+
+func Test_Eventually(t *testing.T) {
+	t.Run("should pass once assertion holds", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+		calls := 0
+
+		// when
+		ok := Eventually(fake, retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(5)), func() error {
+			calls++
+			if calls < 3 {
+				return errFlaky
+			}
+			return nil
+		})
+
+		// then
+		assert.True(t, ok)
+		assert.Empty(t, fake.errors)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("should report last failure", func(t *testing.T) {
+		// given
+		fake := &fakeT{}
+		calls := 0
+
+		// when
+		ok := Eventually(fake, retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(3)), func() error {
+			calls++
+			return fmt.Errorf("expected 3 replicas but got %d", calls)
+		})
+
+		// then
+		assert.False(t, ok)
+		require.Len(t, fake.errors, 1)
+		assert.Regexp(t, `^assertion not satisfied after 3 attempt\(s\) in .+ \(exhausted\): expected 3 replicas but got 3$`, fake.errors[0])
+	})
+}
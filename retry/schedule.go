@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a Backoff waiting until the next point in time of a schedule instead of a relative delay, e.g. the top
+// of the next hour. It suits operations that are pointless to retry quickly, like nightly synchronizations with a
+// system that is down for maintenance. Such long delays usually come with a longer limit set with WithLimit, or are
+// kept across restarts with an Outbox. The delays are computed with the clock set with WithClock and are not
+// varied by WithJitterPercent or WithFullJitter, so that attempts start exactly at the points in time.
+type Schedule struct {
+	// Next returns the first point in time of the schedule after the given one. A zero time means that the schedule
+	// has no further point in time.
+	Next func(after time.Time) time.Time
+}
+
+// Delay returns the time until the next point in time of the schedule, regardless of the attempt. Without further
+// point in time, it returns the maximum duration. A Retrier gives up with an ExhaustedError in that case instead.
+func (s Schedule) Delay(int) time.Duration {
+	delay, ok := s.delayAt(time.Now())
+	if !ok {
+		return math.MaxInt64
+	}
+	return delay
+}
+
+// delayAt returns the time from now until the next point in time of the schedule and false if there is none.
+func (s Schedule) delayAt(now time.Time) (time.Duration, bool) {
+	next := s.Next(now)
+	if next.IsZero() {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
+
+// Cron returns a Schedule of the given cron expression with the five fields minute, hour, day of month, month and
+// day of week, e.g. "0 * * * *" for the top of every hour or "30 2 * * 1-5" for 2:30 on workdays. Each field is
+// either *, a number, a range like 1-5 or a list of them, all optionally followed by a step like */15. Days of the
+// week range from 0 for Sunday to 7 for Sunday again. If both the day of month and the day of week are restricted,
+// i.e. do not start with *, a day matches if either does, like in crontab. The points in time are computed in the
+// location of the clock.
+func Cron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: expected 5 fields but got %d", expr, len(fields))
+	}
+
+	spec := &cronSpec{}
+	var err error
+	bounds := []struct {
+		target   *[]bool
+		min, max int
+	}{
+		{&spec.minutes, 0, 59},
+		{&spec.hours, 0, 23},
+		{&spec.days, 1, 31},
+		{&spec.months, 1, 12},
+		{&spec.weekdays, 0, 7},
+	}
+	for i, bound := range bounds {
+		*bound.target, err = parseCronField(fields[i], bound.min, bound.max)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+	spec.weekdays[0] = spec.weekdays[0] || spec.weekdays[7]
+	// Like in Vixie cron, fields starting with * count as unrestricted, even with a step.
+	spec.anyDay = strings.HasPrefix(fields[2], "*")
+	spec.anyWeekday = strings.HasPrefix(fields[4], "*")
+
+	return Schedule{Next: spec.next}, nil
+}
+
+// MustCron works like Cron but panics if the expression is invalid. It simplifies the initialization of variables.
+func MustCron(expr string) Schedule {
+	schedule, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+// cronSpec holds the matching values of the fields of a cron expression, indexed by the value.
+type cronSpec struct {
+	minutes, hours, days, months, weekdays []bool
+	anyDay, anyWeekday                     bool
+}
+
+// cronHorizon limits the search for the next point in time of expressions that never match, like "0 0 30 2 *".
+const cronHorizon = 5
+
+func (c *cronSpec) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	horizon := t.AddDate(cronHorizon, 0, 0)
+	for t.Before(horizon) {
+		switch {
+		case !c.months[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSpec) dayMatches(t time.Time) bool {
+	day, weekday := c.days[t.Day()], c.weekdays[t.Weekday()]
+	if !c.anyDay && !c.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// parseCronField returns the values matched by a field of a cron expression, indexed by the value.
+func parseCronField(field string, min, max int) ([]bool, error) {
+	matches := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepText)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		first, last := min, max
+		if valueRange != "*" {
+			firstText, lastText, isRange := strings.Cut(valueRange, "-")
+			var err error
+			first, err = strconv.Atoi(firstText)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in %q", part)
+			}
+			last = first
+			if isRange {
+				last, err = strconv.Atoi(lastText)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if hasStep {
+				last = max
+			}
+		}
+		if first < min || last > max || first > last {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for value := first; value <= last; value += step {
+			matches[value] = true
+		}
+	}
+	return matches, nil
+}
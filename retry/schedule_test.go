@@ -0,0 +1,121 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Cron(t *testing.T) {
+	now := time.Date(2024, 11, 15, 8, 20, 30, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"top of the next hour", "0 * * * *", time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC)},
+		{"next minute", "* * * * *", time.Date(2024, 11, 15, 8, 21, 0, 0, time.UTC)},
+		{"every quarter hour", "*/15 * * * *", time.Date(2024, 11, 15, 8, 30, 0, 0, time.UTC)},
+		{"nightly", "30 2 * * *", time.Date(2024, 11, 16, 2, 30, 0, 0, time.UTC)},
+		{"workdays", "0 6 * * 1-5", time.Date(2024, 11, 18, 6, 0, 0, 0, time.UTC)},
+		{"sunday as seven", "0 0 * * 7", time.Date(2024, 11, 17, 0, 0, 0, 0, time.UTC)},
+		{"list of hours", "0 4,12,20 * * *", time.Date(2024, 11, 15, 12, 0, 0, 0, time.UTC)},
+		{"first of month", "0 0 1 * *", time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 1 *", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 20 * 0", time.Date(2024, 11, 17, 0, 0, 0, 0, time.UTC)},
+		{"stepped day of month and weekday", "0 0 */2 * 1", time.Date(2024, 11, 25, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			sut, err := Cron(tt.expr)
+			require.NoError(t, err)
+
+			// when
+			actual := sut.Next(now)
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("should have no next time for impossible date", func(t *testing.T) {
+		sut := MustCron("0 0 30 2 *")
+		assert.True(t, sut.Next(now).IsZero())
+		_, ok := sut.delayAt(now)
+		assert.False(t, ok)
+		assert.Equal(t, time.Duration(1<<63-1), sut.Delay(1))
+	})
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *", "1-b * * * *"} {
+		t.Run("should reject "+expr, func(t *testing.T) {
+			_, err := Cron(expr)
+			assert.ErrorContains(t, err, "invalid cron expression")
+			assert.Panics(t, func() { MustCron(expr) })
+		})
+	}
+}
+
+func Test_Schedule_Delay(t *testing.T) {
+	t.Run("should wait until next time of clock", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 20, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(MustCron("0 * * * *")), WithLimit(24*time.Hour), WithMaxTries(3))
+		var starts []time.Time
+
+		// when
+		err := sut.Do(context.Background(), func(context.Context) error {
+			starts = append(starts, clock.Now())
+			return errors.New("maintenance")
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 11, 15, 8, 20, 0, 0, time.UTC),
+			time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, 11, 15, 10, 0, 0, 0, time.UTC),
+		}, starts)
+	})
+	t.Run("should give up once schedule has no further time", func(t *testing.T) {
+		// given
+		clock := newWaitingClock()
+		sut := New(WithClock(clock), WithBackoff(MustCron("0 0 30 2 *")), WithLimit(0))
+		calls := 0
+
+		// when
+		err := sut.Do(context.Background(), func(context.Context) error {
+			calls++
+			return assert.AnError
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, clock.timers)
+	})
+	t.Run("should not apply jitter", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 20, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(MustCron("0 * * * *")), WithJitterPercent(50))
+
+		// when
+		delay := sut.DelayFor(1)
+
+		// then
+		assert.Equal(t, 40*time.Minute, delay)
+	})
+	t.Run("should compute delay from wall clock", func(t *testing.T) {
+		sut := Schedule{Next: func(after time.Time) time.Time { return after.Add(time.Hour) }}
+		assert.InDelta(t, float64(time.Hour), float64(sut.Delay(1)), float64(time.Second))
+	})
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// longDelayThreshold marks the backoff delay from which on a wait is checked against the wall clock as well.
+const longDelayThreshold = time.Minute
+
+// spinThreshold marks the delay below which a wait yields the processor instead of creating a timer. Timers cost
+// more than such tiny delays themselves.
+const spinThreshold = 100 * time.Microsecond
+
+// wallClockCheckInterval defines how often a long wait compares its progress with the wall clock.
+var wallClockCheckInterval = 10 * time.Second
+
+// sleep waits for the given delay and returns false if ctx is done before. Receiving from wake ends the wait early.
+func sleep(ctx context.Context, delay time.Duration, wake <-chan struct{}) bool {
+	if delay < spinThreshold {
+		return spin(ctx, delay)
+	}
+	if delay >= longDelayThreshold {
+		return sleepWallClock(ctx, delay, wallClockCheckInterval, wake)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-wake:
+		return true
+	case <-timer.C:
+		return true
+	}
+}
+
+// spin waits for tiny delays by repeatedly yielding the processor until the delay has passed.
+func spin(ctx context.Context, delay time.Duration) bool {
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+		}
+		if time.Since(start) >= delay {
+			return true
+		}
+		runtime.Gosched()
+	}
+}
+
+// sleepWallClock waits in chunks until either the monotonic or the wall clock has advanced by the given delay.
+//
+// Go timers are based on the monotonic clock which does not advance while the system is suspended. A multi-minute
+// timer therefore fires far too late on a laptop or VM that was paused in between. The wall clock does include such
+// pauses but may jump backwards on clock adjustments, which the monotonic clock guards against.
+func sleepWallClock(ctx context.Context, delay time.Duration, checkInterval time.Duration, wake <-chan struct{}) bool {
+	start := time.Now()
+	for {
+		remaining := delay - elapsedSince(start)
+		if remaining <= 0 {
+			return true
+		}
+
+		timer := time.NewTimer(min(remaining, checkInterval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-wake:
+			timer.Stop()
+			return true
+		case <-timer.C:
+		}
+	}
+}
+
+// elapsedSince returns the time passed since start, preferring whichever of the monotonic and the wall clock
+// advanced further. This way, periods of system suspend are accounted for in retry limits.
+func elapsedSince(start time.Time) time.Duration {
+	monotonic := time.Since(start)
+	wall := time.Now().Round(0).Sub(start.Round(0))
+	return max(monotonic, wall)
+}
@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sleep(t *testing.T) {
+	t.Run("should wait for short delay", func(t *testing.T) {
+		// given
+		start := time.Now()
+
+		// when
+		ok := sleep(context.Background(), 10*time.Millisecond, nil)
+
+		// then
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+	t.Run("should spin for tiny delay", func(t *testing.T) {
+		// given
+		start := time.Now()
+
+		// when
+		ok := sleep(context.Background(), 20*time.Microsecond, nil)
+
+		// then
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Microsecond)
+	})
+	t.Run("should return false on canceled context", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		ok := sleep(ctx, time.Hour, nil)
+
+		// then
+		assert.False(t, ok)
+	})
+	t.Run("should stop spinning on canceled context", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		ok := sleep(ctx, 50*time.Microsecond, nil)
+
+		// then
+		assert.False(t, ok)
+	})
+}
+
+func Test_sleepWallClock(t *testing.T) {
+	t.Run("should wait in chunks for the whole delay", func(t *testing.T) {
+		// given
+		start := time.Now()
+
+		// when
+		ok := sleepWallClock(context.Background(), 30*time.Millisecond, 5*time.Millisecond, nil)
+
+		// then
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+	t.Run("should return false on canceled context", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		ok := sleepWallClock(ctx, time.Hour, 5*time.Millisecond, nil)
+
+		// then
+		assert.False(t, ok)
+	})
+}
+
+func Test_elapsedSince(t *testing.T) {
+	t.Run("should account for wall clock time", func(t *testing.T) {
+		// given
+		start := time.Now().Add(-time.Hour).Round(0)
+
+		// when
+		actual := elapsedSince(start)
+
+		// then
+		assert.GreaterOrEqual(t, actual, time.Hour)
+	})
+	t.Run("should be at least the monotonic time", func(t *testing.T) {
+		// given
+		start := time.Now()
+		time.Sleep(5 * time.Millisecond)
+
+		// when
+		actual := elapsedSince(start)
+
+		// then
+		assert.GreaterOrEqual(t, actual, 5*time.Millisecond)
+	})
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+)
+
+// AttemptStack is the stack trace captured by Trace during a failed attempt.
+type AttemptStack struct {
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	// Stack is the formatted stack trace of the goroutine that called Trace.
+	Stack string
+}
+
+// WithStackTraces enables capturing stack traces with Trace. The stack traces of all failed attempts are attached to
+// the ExhaustedError, so that it is visible which code path inside a large workload failed on which attempt.
+// Capturing stack traces is expensive and thus disabled by default.
+func WithStackTraces() Option {
+	return func(p *policy) {
+		p.stackTraces = true
+	}
+}
+
+// tracedError attaches a stack trace to an error.
+type tracedError struct {
+	err   error
+	stack string
+}
+
+func (e *tracedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tracedError) Unwrap() error {
+	return e.err
+}
+
+// Trace attaches the stack trace of the calling goroutine to err if stack traces were enabled with
+// WithStackTraces for the attempt ctx belongs to. Otherwise, it returns err unchanged, so it is cheap to call on
+// every error path of a workload. Trace returns nil if err is nil.
+func Trace(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	scope, ok := ctx.Value(attemptKey{}).(*attemptScope)
+	if !ok || !scope.captureStacks {
+		return err
+	}
+	return &tracedError{err: err, stack: string(debug.Stack())}
+}
+
+// stackOf returns the stack trace attached to err by Trace, if any.
+func stackOf(err error) (string, bool) {
+	var traced *tracedError
+	if !errors.As(err, &traced) {
+		return "", false
+	}
+	return traced.stack, true
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failInStep(ctx context.Context, step int) error {
+	return Trace(ctx, fmt.Errorf("step %d: %w", step, assert.AnError))
+}
+
+func Test_WithStackTraces(t *testing.T) {
+	t.Run("should attach stack traces of failed attempts to exhaustion", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3), WithStackTraces())
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 2 {
+				return assert.AnError
+			}
+			return failInStep(ctx, attempts)
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		require.Len(t, exhaustedErr.Stacks, 2)
+		assert.Equal(t, 1, exhaustedErr.Stacks[0].Attempt)
+		assert.Equal(t, 3, exhaustedErr.Stacks[1].Attempt)
+		assert.Contains(t, exhaustedErr.Stacks[0].Stack, "failInStep")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.EqualError(t, exhaustedErr.Err, "step 3: "+assert.AnError.Error())
+	})
+	t.Run("should not capture stack traces by default", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return failInStep(ctx, 1)
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Empty(t, exhaustedErr.Stacks)
+		var traced *tracedError
+		assert.False(t, errors.As(err, &traced))
+	})
+	t.Run("should return nil for nil error", func(t *testing.T) {
+		assert.NoError(t, Trace(context.Background(), nil))
+	})
+}
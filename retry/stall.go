@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NoProgressError is returned instead of retrying if the workload did not report any progress within the window set
+// with WithProgressDeadline.
+type NoProgressError struct {
+	// Window is the configured time within which progress is expected.
+	Window time.Duration
+	// Attempts contains the number of times the workload was executed.
+	Attempts int
+	// Err contains the error of the last attempt.
+	Err error
+}
+
+// Error returns the error's string representation.
+func (e *NoProgressError) Error() string {
+	return fmt.Sprintf("no progress was reported within %s: %s", e.Window, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *NoProgressError) Unwrap() error {
+	return e.Err
+}
+
+// WithProgressDeadline ends the retry loop with a NoProgressError once the workload has not reported any progress
+// with ReportProgress within the given window, instead of retrying until the other limits are reached. This detects
+// workloads that are stuck and fail identically on every attempt, e.g. a migration tripping over the same record.
+// Only a report differing from the previous one counts as progress. The window starts with the retry loop and is
+// checked after every failed attempt. A window of zero or less disables the deadline.
+func WithProgressDeadline(window time.Duration) Option {
+	return func(p *policy) {
+		p.progressWindow = window
+	}
+}
+
+// progressTracker remembers when the workload made progress the last time.
+type progressTracker struct {
+	mu       sync.Mutex
+	last     time.Time
+	previous Progress
+}
+
+func (t *progressTracker) observe(now time.Time, progress Progress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if progress != t.previous {
+		t.previous = progress
+		t.last = now
+	}
+}
+
+func (t *progressTracker) lastProgress() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// progressCallback returns the callback ReportProgress passes the progress to, which also feeds tracker if it is
+// not nil. It returns nil if there is neither a callback nor a tracker.
+func (p *policy) progressCallback(tracker *progressTracker) func(Progress) {
+	if tracker == nil {
+		return p.onProgress
+	}
+
+	return func(progress Progress) {
+		tracker.observe(p.now(), progress)
+		if p.onProgress != nil {
+			p.onProgress(progress)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithProgressDeadline(t *testing.T) {
+	t.Run("should abort without progress", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithLimit(0), WithProgressDeadline(5*time.Minute))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			ReportProgress(ctx, Progress{Items: 1})
+			return assert.AnError
+		})
+
+		// then
+		var noProgressErr *NoProgressError
+		require.ErrorAs(t, err, &noProgressErr)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 5*time.Minute, noProgressErr.Window)
+		assert.Equal(t, 7, noProgressErr.Attempts)
+		assert.Equal(t, 7, attempts)
+	})
+	t.Run("should keep retrying while making progress", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		var reported []Progress
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithLimit(0), WithMaxTries(20),
+			WithProgressDeadline(90*time.Second), WithProgress(func(progress Progress) {
+				reported = append(reported, progress)
+			}))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			ReportProgress(ctx, Progress{Items: attempts})
+			if attempts < 10 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 10, attempts)
+		assert.Len(t, reported, 10)
+	})
+	t.Run("should report outcome", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithLimit(0), WithProgressDeadline(time.Minute))
+
+		// when
+		report, err := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, OutcomeExhausted, report.Outcome)
+		assert.Len(t, report.Attempts, 3)
+	})
+}
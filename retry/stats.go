@@ -0,0 +1,30 @@
+package retry
+
+import "sync/atomic"
+
+// stats counts the attempts, retries and exhaustions of all retriers of the process.
+var stats struct {
+	attempts  atomic.Int64
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+// Stats contains process-wide counters of all retriers. They only grow and give a basic insight into the retry
+// behaviour of a binary without depending on a metrics library.
+type Stats struct {
+	// Attempts counts how often workloads were executed, including the first attempt.
+	Attempts int64 `json:"attempts"`
+	// Retries counts how often a failed workload was scheduled for another attempt.
+	Retries int64 `json:"retries"`
+	// Exhausted counts the retry loops that ended because a limit was reached.
+	Exhausted int64 `json:"exhausted"`
+}
+
+// GlobalStats returns the current counters of all retriers. The package retry/retryexpvar publishes them via expvar.
+func GlobalStats() Stats {
+	return Stats{
+		Attempts:  stats.attempts.Load(),
+		Retries:   stats.retries.Load(),
+		Exhausted: stats.exhausted.Load(),
+	}
+}
@@ -0,0 +1,26 @@
+package retry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GlobalStats(t *testing.T) {
+	// given
+	before := GlobalStats()
+	sut := New(WithBackoff(Constant(0)), WithMaxTries(3))
+
+	// when
+	err := sut.Do(context.Background(), func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	// then
+	assert.ErrorIs(t, err, assert.AnError)
+	after := GlobalStats()
+	assert.GreaterOrEqual(t, after.Attempts-before.Attempts, int64(3))
+	assert.GreaterOrEqual(t, after.Retries-before.Retries, int64(2))
+	assert.GreaterOrEqual(t, after.Exhausted-before.Exhausted, int64(1))
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Step is one step of a sequential workload executed by Steps.
+type Step struct {
+	// Name identifies the step in errors.
+	Name string
+	// Run performs the step. It is retried on its own, so it only has to be idempotent in itself.
+	Run func(ctx context.Context) error
+	// Compensate reverts the changes of Run if a later step fails for good. It is optional and retried with the
+	// Retrier of the step as well.
+	Compensate func(ctx context.Context) error
+	// Retrier retries Run and Compensate according to its policy. If it is nil, a Retrier with the default policy is
+	// used.
+	Retrier *Retrier
+}
+
+func (s Step) retrier() *Retrier {
+	if s.Retrier == nil {
+		return New()
+	}
+	return s.Retrier
+}
+
+// StepError is returned by Steps if a step failed for good.
+type StepError struct {
+	// Step is the name of the failed step.
+	Step string
+	// Index is the index of the failed step. All steps before it have completed.
+	Index int
+	// Err is the error of the step's retry loop.
+	Err error
+	// CompensationErr joins the errors of the compensations that failed, if any.
+	CompensationErr error
+}
+
+func (e *StepError) Error() string {
+	if e.CompensationErr != nil {
+		return fmt.Sprintf("step %d (%s) failed: %s; compensation failed: %s", e.Index+1, e.Step, e.Err, e.CompensationErr)
+	}
+	return fmt.Sprintf("step %d (%s) failed: %s", e.Index+1, e.Step, e.Err)
+}
+
+// Unwrap returns the error of the step's retry loop.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Steps executes the steps one after another, each retried with its own Retrier, e.g. the stages of an installation.
+// A failing step is retried by itself without repeating the steps before it. If a step fails for good, Steps
+// skips the remaining steps and calls the Compensate functions of the completed steps in reverse order, so that
+// partially applied changes are rolled back like in a saga. The failed step itself is not compensated. The
+// compensations run even if ctx is done, bounded by the policies of their Retriers, and a failing compensation does
+// not prevent the others. Steps returns a StepError then. See StepsWithTracker for resuming from the failed step
+// later instead.
+func Steps(ctx context.Context, steps []Step) error {
+	return StepsWithTracker(ctx, steps, nil)
+}
+
+// StepsWithTracker works like Steps but records the completed steps by their index in tracker and skips the ones
+// marked done already. Calling it again with the same tracker after a failure resumes from the failed step. A
+// persistent tracker allows resuming even after a restart of the process. If tracker is nil, the progress is kept
+// in memory. Steps skipped as done are compensated as well, so a tracker must not be reused once steps with
+// Compensate functions were rolled back.
+func StepsWithTracker(ctx context.Context, steps []Step, tracker PartTracker) error {
+	if tracker == nil {
+		tracker = &memoryPartTracker{done: map[int]bool{}}
+	}
+
+	for i, step := range steps {
+		if tracker.Done(i) {
+			continue
+		}
+
+		err := step.retrier().Do(ctx, step.Run)
+		if err != nil {
+			return &StepError{Step: step.Name, Index: i, Err: err, CompensationErr: compensate(ctx, steps[:i])}
+		}
+		tracker.MarkDone(i)
+	}
+	return nil
+}
+
+// compensate calls the Compensate functions of the completed steps in reverse order and joins their errors.
+func compensate(ctx context.Context, completed []Step) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := step.retrier().Do(ctx, step.Compensate); err != nil {
+			errs = append(errs, fmt.Errorf("failed to compensate step %d (%s): %w", i+1, step.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Steps(t *testing.T) {
+	t.Run("should retry failed step without repeating previous ones", func(t *testing.T) {
+		// given
+		var executed []string
+		retrier := New(WithBackoff(Constant(0)), WithMaxTries(3))
+		failures := 1
+		steps := []Step{
+			{Name: "database", Retrier: retrier, Run: func(ctx context.Context) error {
+				executed = append(executed, "database")
+				return nil
+			}},
+			{Name: "schema", Retrier: retrier, Run: func(ctx context.Context) error {
+				executed = append(executed, "schema")
+				if failures > 0 {
+					failures--
+					return assert.AnError
+				}
+				return nil
+			}},
+			{Name: "admin", Retrier: retrier, Run: func(ctx context.Context) error {
+				executed = append(executed, "admin")
+				return nil
+			}},
+		}
+
+		// when
+		err := Steps(context.Background(), steps)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"database", "schema", "schema", "admin"}, executed)
+	})
+	t.Run("should stop at failed step", func(t *testing.T) {
+		// given
+		var executed []string
+		steps := []Step{
+			{Name: "database", Run: func(ctx context.Context) error {
+				executed = append(executed, "database")
+				return nil
+			}},
+			{Name: "schema", Retrier: New(WithBackoff(Constant(0)), WithMaxTries(2)), Run: func(ctx context.Context) error {
+				executed = append(executed, "schema")
+				return assert.AnError
+			}},
+			{Name: "admin", Run: func(ctx context.Context) error {
+				executed = append(executed, "admin")
+				return nil
+			}},
+		}
+
+		// when
+		err := Steps(context.Background(), steps)
+
+		// then
+		var stepErr *StepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.Equal(t, "schema", stepErr.Step)
+		assert.Equal(t, 1, stepErr.Index)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "step 2 (schema) failed: the maximum number of retries was reached")
+		assert.Equal(t, []string{"database", "schema", "schema"}, executed)
+	})
+}
+
+func Test_StepsWithTracker(t *testing.T) {
+	// given
+	tracker := &memoryPartTracker{done: map[int]bool{}}
+	var executed []string
+	fail := true
+	steps := []Step{
+		{Name: "database", Run: func(ctx context.Context) error {
+			executed = append(executed, "database")
+			return nil
+		}},
+		{Name: "schema", Run: func(ctx context.Context) error {
+			executed = append(executed, "schema")
+			if fail {
+				return Abort(assert.AnError)
+			}
+			return nil
+		}},
+	}
+	require.Error(t, StepsWithTracker(context.Background(), steps, tracker))
+	fail = false
+
+	// when
+	err := StepsWithTracker(context.Background(), steps, tracker)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"database", "schema", "schema"}, executed)
+}
+
+func Test_Steps_compensation(t *testing.T) {
+	t.Run("should compensate completed steps in reverse order", func(t *testing.T) {
+		// given
+		var executed []string
+		record := func(name string, err error) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				executed = append(executed, name)
+				return err
+			}
+		}
+		steps := []Step{
+			{Name: "database", Run: record("create database", nil), Compensate: record("drop database", nil)},
+			{Name: "user", Run: record("create user", nil)},
+			{Name: "schema", Run: record("create schema", nil), Compensate: record("drop schema", nil)},
+			{Name: "admin", Run: record("create admin", Abort(assert.AnError)), Compensate: record("delete admin", nil)},
+		}
+
+		// when
+		err := Steps(context.Background(), steps)
+
+		// then
+		var stepErr *StepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.NoError(t, stepErr.CompensationErr)
+		assert.Equal(t, []string{"create database", "create user", "create schema", "create admin", "drop schema", "drop database"}, executed)
+	})
+	t.Run("should continue after failed compensation", func(t *testing.T) {
+		// given
+		compensationErr := errors.New("drop failed")
+		dropped := false
+		retrier := New(WithBackoff(Constant(0)), WithMaxTries(1))
+		steps := []Step{
+			{Name: "database", Retrier: retrier, Run: func(ctx context.Context) error { return nil }, Compensate: func(ctx context.Context) error {
+				dropped = true
+				return nil
+			}},
+			{Name: "schema", Retrier: retrier, Run: func(ctx context.Context) error { return nil }, Compensate: func(ctx context.Context) error {
+				return compensationErr
+			}},
+			{Name: "admin", Retrier: retrier, Run: func(ctx context.Context) error { return assert.AnError }},
+		}
+
+		// when
+		err := Steps(context.Background(), steps)
+
+		// then
+		var stepErr *StepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.ErrorIs(t, stepErr.CompensationErr, compensationErr)
+		assert.ErrorContains(t, err, "compensation failed: failed to compensate step 2 (schema)")
+		assert.True(t, dropped)
+	})
+	t.Run("should compensate with canceled context", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		var compensationCtxErr error
+		steps := []Step{
+			{Name: "database", Run: func(ctx context.Context) error { return nil }, Compensate: func(ctx context.Context) error {
+				compensationCtxErr = ctx.Err()
+				return nil
+			}},
+			{Name: "schema", Run: func(ctx context.Context) error {
+				cancel()
+				return assert.AnError
+			}},
+		}
+
+		// when
+		err := Steps(ctx, steps)
+
+		// then
+		require.Error(t, err)
+		assert.NoError(t, compensationCtxErr)
+	})
+}
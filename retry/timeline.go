@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// AttemptRecord describes a single execution of a workload.
+type AttemptRecord struct {
+	// Attempt is the number of the execution, starting with 1.
+	Attempt int
+	// Start is the point in time the execution began.
+	Start time.Time
+	// Duration is the time the workload took.
+	Duration time.Duration
+	// Err is the error returned by the workload, if any.
+	Err error
+	// Delay is the backoff waited for after this attempt. It is zero for the last attempt.
+	Delay time.Duration
+}
+
+type attemptRecordJSON struct {
+	Attempt  int       `json:"attempt"`
+	Start    time.Time `json:"start"`
+	Duration string    `json:"duration"`
+	Error    string    `json:"error,omitempty"`
+	Delay    string    `json:"delay,omitempty"`
+}
+
+// MarshalJSON encodes the record with human-readable durations and the error as string.
+func (a AttemptRecord) MarshalJSON() ([]byte, error) {
+	record := attemptRecordJSON{
+		Attempt:  a.Attempt,
+		Start:    a.Start,
+		Duration: a.Duration.String(),
+	}
+	if a.Err != nil {
+		record.Error = a.Err.Error()
+	}
+	if a.Delay > 0 {
+		record.Delay = a.Delay.String()
+	}
+
+	return json.Marshal(record)
+}
+
+// Timeline contains every attempt of a single retry loop.
+type Timeline struct {
+	// Start is the point in time the retry loop began.
+	Start time.Time `json:"start"`
+	// Attempts lists all executions of the workload in order.
+	Attempts []AttemptRecord `json:"attempts"`
+}
+
+// WriteJSON writes the timeline as JSON to w.
+func (t *Timeline) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+// WithTimeline records the timeline of every retry loop. Once a loop is exhausted, its timeline is attached to the
+// ExhaustedError and written as JSON to w, if w is not nil. This helps attaching the full history of a flaky
+// dependency to bug reports.
+func WithTimeline(w io.Writer) Option {
+	return func(p *policy) {
+		p.recordTimeline = true
+		p.timelineWriter = w
+	}
+}
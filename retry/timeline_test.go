@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithTimeline(t *testing.T) {
+	t.Run("should dump timeline on exhaustion", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		sut := New(WithTimeline(buf), WithMaxTries(3), WithBackoff(Constant(time.Millisecond)))
+		fn := func(ctx context.Context) error {
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		require.NotNil(t, exhaustedErr.Timeline)
+		require.Len(t, exhaustedErr.Timeline.Attempts, 3)
+		assert.Equal(t, time.Millisecond, exhaustedErr.Timeline.Attempts[0].Delay)
+		assert.Equal(t, time.Duration(0), exhaustedErr.Timeline.Attempts[2].Delay)
+
+		var dumped map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &dumped))
+		attempts := dumped["attempts"].([]any)
+		require.Len(t, attempts, 3)
+		first := attempts[0].(map[string]any)
+		assert.Equal(t, float64(1), first["attempt"])
+		assert.Equal(t, assert.AnError.Error(), first["error"])
+		assert.Equal(t, "1ms", first["delay"])
+	})
+	t.Run("should not dump timeline on success", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		sut := New(WithTimeline(buf))
+		fn := func(ctx context.Context) error {
+			return nil
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, buf.String())
+	})
+	t.Run("should attach timeline without writer", func(t *testing.T) {
+		// given
+		sut := New(WithTimeline(nil), WithMaxTries(1))
+		fn := func(ctx context.Context) error {
+			return assert.AnError
+		}
+
+		// when
+		err := sut.Do(context.Background(), fn)
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		require.Len(t, exhaustedErr.Timeline.Attempts, 1)
+	})
+}
+
+func Test_AttemptRecord_MarshalJSON(t *testing.T) {
+	// given
+	sut := AttemptRecord{Attempt: 2, Start: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC), Duration: 1500 * time.Millisecond}
+
+	// when
+	actual, err := json.Marshal(sut)
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"attempt":2,"start":"2024-11-15T08:00:00Z","duration":"1.5s"}`, string(actual))
+}
@@ -0,0 +1,32 @@
+package retry
+
+import "context"
+
+// WithTrigger lets an external event start the next attempt instead of a timer, e.g. a change reported by an
+// informer. After a failed attempt, the Retrier waits for the backoff delay as minimum spacing and afterward until
+// it receives from trigger. Events sent during the backoff are only seen if the channel buffers them or the sender
+// blocks. Kick ends both waits, so a kick during the backoff starts the next attempt without waiting for an event.
+// The time waiting for an event counts toward the limit set with WithLimit, so reconcile-on-change loops usually
+// remove the limit.
+func WithTrigger(trigger <-chan struct{}) Option {
+	return func(p *policy) {
+		p.trigger = trigger
+	}
+}
+
+// awaitTrigger waits for the next event of the configured trigger, if any, and returns false if ctx is done before.
+// kicked is the channel of the preceding backoff wait, so that a kick during the backoff skips the trigger as well.
+func (r *Retrier) awaitTrigger(ctx context.Context, p *policy, kicked <-chan struct{}) bool {
+	if p.trigger == nil {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-p.trigger:
+		return true
+	case <-kicked:
+		return true
+	}
+}
@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithTrigger(t *testing.T) {
+	t.Run("should wait for event after backoff", func(t *testing.T) {
+		// given
+		trigger := make(chan struct{}, 1)
+		sut := New(WithTrigger(trigger), WithBackoff(Constant(time.Millisecond)), WithLimit(0))
+		calls := &atomic.Int32{}
+		result := make(chan error, 1)
+		go func() {
+			result <- sut.Do(context.Background(), func(ctx context.Context) error {
+				if calls.Add(1) == 1 {
+					return assert.AnError
+				}
+				return nil
+			})
+		}()
+
+		// when
+		time.Sleep(20 * time.Millisecond)
+		callsBeforeEvent := calls.Load()
+		trigger <- struct{}{}
+
+		// then
+		require.NoError(t, <-result)
+		assert.Equal(t, int32(1), callsBeforeEvent)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should keep backoff as minimum spacing", func(t *testing.T) {
+		// given
+		trigger := make(chan struct{}, 1)
+		trigger <- struct{}{}
+		sut := New(WithTrigger(trigger), WithBackoff(Constant(20*time.Millisecond)), WithMaxTries(2))
+		start := time.Now()
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Error(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+	t.Run("should skip event on kick during backoff", func(t *testing.T) {
+		// given
+		clock := newWaitingClock()
+		sut := New(WithTrigger(make(chan struct{})), WithClock(clock), WithBackoff(Constant(time.Hour)), WithLimit(0))
+		calls := 0
+		result := make(chan error, 1)
+		go func() {
+			result <- sut.Do(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls == 1 {
+					return assert.AnError
+				}
+				return nil
+			})
+		}()
+		<-clock.timers
+
+		// when
+		sut.Kick()
+
+		// then
+		select {
+		case err := <-result:
+			assert.NoError(t, err)
+			assert.Equal(t, 2, calls)
+		case <-time.After(time.Second):
+			t.Fatal("retry loop waits for trigger after kick")
+		}
+	})
+	t.Run("should stop waiting on canceled context", func(t *testing.T) {
+		// given
+		sut := New(WithTrigger(make(chan struct{})), WithBackoff(Constant(time.Millisecond)))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := sut.Do(ctx, func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("should run loop on events", func(t *testing.T) {
+		// given
+		trigger := make(chan struct{})
+		sut := New(WithTrigger(trigger))
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+
+		// when
+		go func() {
+			trigger <- struct{}{}
+			trigger <- struct{}{}
+		}()
+		err := RunLoop(ctx, sut, time.Millisecond, func(ctx context.Context) error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return nil
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 3, calls)
+	})
+}
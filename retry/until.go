@@ -0,0 +1,39 @@
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrConditionNotMet is the error of the attempts of Until whose condition was not met yet. After the last permitted
+// attempt, it is wrapped by the resulting ExhaustedError.
+var ErrConditionNotMet = errors.New("condition not met")
+
+// Until polls cond until it reports done, similar to wait.PollUntilContextTimeout of the k8s.io/apimachinery
+// module but with the policy of r in place of a fixed interval. This way, waiting for a condition and retrying on
+// errors share the same configuration. An unmet condition is always polled again until the limits of r are
+// reached. An error of cond is handled like in Retrier.Do, i.e. it is retried if the classifier of r says so and
+// returned otherwise.
+func Until(ctx context.Context, r *Retrier, cond func(ctx context.Context) (done bool, err error)) error {
+	return r.Do(ctx, func(ctx context.Context) error {
+		done, err := cond(ctx)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return ErrConditionNotMet
+		}
+		return nil
+	}, retryUnmetConditions)
+}
+
+// retryUnmetConditions retries ErrConditionNotMet regardless of the configured classifier.
+func retryUnmetConditions(p *policy) {
+	classifier := p.classifier
+	p.classifier = ClassifierFunc(func(err error) Decision {
+		if errors.Is(err, ErrConditionNotMet) {
+			return DecisionRetry
+		}
+		return classifier.Classify(err)
+	})
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Until(t *testing.T) {
+	fastRetrier := func(opts ...Option) *Retrier {
+		return New(append([]Option{WithBackoff(Constant(time.Millisecond)), WithMaxTries(5)}, opts...)...)
+	}
+
+	t.Run("should poll until condition is met", func(t *testing.T) {
+		// given
+		polls := 0
+
+		// when
+		err := Until(context.Background(), fastRetrier(), func(ctx context.Context) (bool, error) {
+			polls++
+			return polls == 3, nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, polls)
+	})
+	t.Run("should fail if condition is never met", func(t *testing.T) {
+		// when
+		err := Until(context.Background(), fastRetrier(), func(ctx context.Context) (bool, error) {
+			return false, nil
+		})
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.ErrorIs(t, err, ErrConditionNotMet)
+		assert.Equal(t, 5, exhaustedErr.Attempts)
+	})
+	t.Run("should poll unmet condition regardless of classifier", func(t *testing.T) {
+		// given
+		polls := 0
+
+		// when
+		err := Until(context.Background(), fastRetrier(WithRetriable(TestableRetryFunc)), func(ctx context.Context) (bool, error) {
+			polls++
+			return polls == 2, nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, polls)
+	})
+	t.Run("should retry retriable errors of condition", func(t *testing.T) {
+		// given
+		polls := 0
+
+		// when
+		err := Until(context.Background(), fastRetrier(), func(ctx context.Context) (bool, error) {
+			polls++
+			if polls == 1 {
+				return false, assert.AnError
+			}
+			return true, nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, polls)
+	})
+	t.Run("should return non-retriable errors of condition", func(t *testing.T) {
+		// given
+		polls := 0
+
+		// when
+		err := Until(context.Background(), fastRetrier(WithRetriable(TestableRetryFunc)), func(ctx context.Context) (bool, error) {
+			polls++
+			return false, assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, polls)
+	})
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type attemptKey struct{}
+
+// attemptScope is stored in the context of an attempt.
+type attemptScope struct {
+	info          AttemptInfo
+	values        AttemptValues
+	captureStacks bool
+}
+
+// attemptContext carries the attemptScope of an attempt. It replaces context.WithValue, which would allocate the
+// scope and the context separately for every attempt.
+type attemptContext struct {
+	context.Context
+	scope attemptScope
+}
+
+// Value returns the attemptScope for attemptKey and otherwise the value of the parent context.
+func (c *attemptContext) Value(key any) any {
+	if key == (attemptKey{}) {
+		return &c.scope
+	}
+	return c.Context.Value(key)
+}
+
+// AttemptInfo describes the attempt a workload is executed in.
+type AttemptInfo struct {
+	// Number is the number of the attempt, starting at 1. In daemon loops of RunLoop, it restarts after every
+	// successful run.
+	Number int
+	// FirstAttempt is the time the first attempt of the retry loop started.
+	FirstAttempt time.Time
+}
+
+// AttemptFromContext returns the attempt ctx belongs to, so that deep call stacks can act differently on retries,
+// e.g. skip an expensive validation or tag their logs. ctx must be the context handed to the workload or one of
+// the hooks. Outside an attempt, AttemptFromContext returns false.
+func AttemptFromContext(ctx context.Context) (AttemptInfo, bool) {
+	scope, ok := ctx.Value(attemptKey{}).(*attemptScope)
+	if !ok {
+		return AttemptInfo{}, false
+	}
+	return scope.info, true
+}
+
+// AttemptValues passes data between the hooks of an attempt and the workload, e.g. a token refreshed by the hook of
+// WithBeforeAttempt or the endpoint it has chosen. Every attempt starts with empty values, so nothing leaks into the
+// next attempt. AttemptValues are safe for concurrent use.
+type AttemptValues struct {
+	mu     sync.Mutex
+	values map[any]any
+}
+
+// AttemptValuesFromContext returns the values of the attempt ctx belongs to. ctx must be the context handed to the
+// workload or one of the hooks. Outside an attempt, AttemptValuesFromContext returns nil.
+func AttemptValuesFromContext(ctx context.Context) *AttemptValues {
+	scope, ok := ctx.Value(attemptKey{}).(*attemptScope)
+	if !ok {
+		return nil
+	}
+	return &scope.values
+}
+
+// Set stores value under key, replacing any previous value. Like for context.WithValue, key should be of an
+// unexported type to avoid collisions.
+func (v *AttemptValues) Set(key, value any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.values == nil {
+		v.values = map[any]any{}
+	}
+	v.values[key] = value
+}
+
+// Get returns the value stored under key and whether there is one.
+func (v *AttemptValues) Get(key any) (any, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	value, ok := v.values[key]
+	return value, ok
+}
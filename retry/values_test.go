@@ -0,0 +1,153 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenKey struct{}
+
+func Test_AttemptValuesFromContext(t *testing.T) {
+	t.Run("should pass values from hook to workload", func(t *testing.T) {
+		// given
+		var cleanedTokens []any
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(3),
+			WithBeforeAttempt(func(ctx context.Context, attempt int) error {
+				AttemptValuesFromContext(ctx).Set(tokenKey{}, attempt*10)
+				return nil
+			}),
+			WithCleanup(func(ctx context.Context, err error) {
+				token, _ := AttemptValuesFromContext(ctx).Get(tokenKey{})
+				cleanedTokens = append(cleanedTokens, token)
+			}))
+		var tokens []any
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			token, ok := AttemptValuesFromContext(ctx).Get(tokenKey{})
+			require.True(t, ok)
+			tokens = append(tokens, token)
+			if len(tokens) < 2 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []any{10, 20}, tokens)
+		assert.Equal(t, []any{10}, cleanedTokens)
+	})
+	t.Run("should start every attempt with empty values", func(t *testing.T) {
+		// given
+		sut := New(WithBackoff(Constant(0)), WithMaxTries(2))
+		var found []bool
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			values := AttemptValuesFromContext(ctx)
+			_, ok := values.Get(tokenKey{})
+			found = append(found, ok)
+			values.Set(tokenKey{}, "stale")
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, []bool{false, false}, found)
+	})
+	t.Run("should return nil outside attempts", func(t *testing.T) {
+		assert.Nil(t, AttemptValuesFromContext(context.Background()))
+	})
+}
+
+func Test_AttemptFromContext(t *testing.T) {
+	t.Run("should provide attempt number and start of first attempt", func(t *testing.T) {
+		// given
+		clock := &fakeClock{now: time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)}
+		sut := New(WithClock(clock), WithBackoff(Constant(time.Minute)), WithMaxTries(3))
+		var infos []AttemptInfo
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			info, ok := AttemptFromContext(ctx)
+			require.True(t, ok)
+			infos = append(infos, info)
+			return assert.AnError
+		})
+
+		// then
+		first := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		assert.Equal(t, []AttemptInfo{{Number: 1, FirstAttempt: first}, {Number: 2, FirstAttempt: first}, {Number: 3, FirstAttempt: first}}, infos)
+	})
+	t.Run("should provide attempt to hooks", func(t *testing.T) {
+		// given
+		var hookInfo AttemptInfo
+		sut := New(WithBeforeAttempt(func(ctx context.Context, attempt int) error {
+			hookInfo, _ = AttemptFromContext(ctx)
+			return nil
+		}))
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, hookInfo.Number)
+		assert.False(t, hookInfo.FirstAttempt.IsZero())
+	})
+	t.Run("should restart numbering in daemon loops after success", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		sut := New(WithBackoff(Constant(time.Millisecond)))
+		var numbers []int
+
+		// when
+		_ = RunLoop(ctx, sut, time.Millisecond, func(ctx context.Context) error {
+			info, _ := AttemptFromContext(ctx)
+			numbers = append(numbers, info.Number)
+			switch len(numbers) {
+			case 2:
+				return nil
+			case 4:
+				cancel()
+			}
+			return assert.AnError
+		})
+
+		// then
+		assert.Equal(t, []int{1, 2, 1, 2}, numbers)
+	})
+	t.Run("should keep context of attempt valid after the loop", func(t *testing.T) {
+		// given
+		sut := New()
+		var retained context.Context
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			retained = ctx
+			return nil
+		})
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			AttemptValuesFromContext(ctx).Set("key", "other loop")
+			return nil
+		})
+
+		// then
+		info, ok := AttemptFromContext(retained)
+		require.True(t, ok)
+		assert.Equal(t, 1, info.Number)
+		_, found := AttemptValuesFromContext(retained).Get("key")
+		assert.False(t, found)
+		assert.NoError(t, retained.Err())
+	})
+	t.Run("should return false outside attempts", func(t *testing.T) {
+		_, ok := AttemptFromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// WaitForTCP blocks until a TCP connection to addr can be established, e.g. to wait for a database before starting
+// an application that requires it. The connection attempts are retried according to r and closed right away.
+func WaitForTCP(ctx context.Context, r *Retrier, addr string) error {
+	var dialer net.Dialer
+	return r.Do(ctx, func(ctx context.Context) error {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		return conn.Close()
+	})
+}
+
+// WaitForHTTP blocks until a GET request to url is answered with expectStatus, e.g. to wait until a service is not
+// only listening but ready to serve requests. The requests are retried according to r, so both network errors and
+// unexpected status codes are retried by default.
+func WaitForHTTP(ctx context.Context, r *Retrier, url string, expectStatus int) error {
+	return r.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return Abort(fmt.Errorf("failed to create request: %w", err))
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+		if resp.StatusCode != expectStatus {
+			return fmt.Errorf("%s answered with status code %d instead of %d", url, resp.StatusCode, expectStatus)
+		}
+		return nil
+	})
+}
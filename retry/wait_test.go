@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastWaitRetrier() *Retrier {
+	return New(WithBackoff(Constant(time.Millisecond)), WithMaxTries(5))
+}
+
+func Test_WaitForTCP(t *testing.T) {
+	t.Run("should succeed once port is open", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		// when
+		err = WaitForTCP(context.Background(), fastWaitRetrier(), listener.Addr().String())
+
+		// then
+		assert.NoError(t, err)
+	})
+	t.Run("should fail if port stays closed", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		// when
+		err = WaitForTCP(context.Background(), fastWaitRetrier(), addr)
+
+		// then
+		var exhaustedErr *ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, 5, exhaustedErr.Attempts)
+		assert.ErrorContains(t, err, "failed to connect to "+addr)
+	})
+}
+
+func Test_WaitForHTTP(t *testing.T) {
+	t.Run("should wait for expected status", func(t *testing.T) {
+		// given
+		calls := &atomic.Int32{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		// when
+		err := WaitForHTTP(context.Background(), fastWaitRetrier(), server.URL, http.StatusOK)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("should fail if status is never reached", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		// when
+		err := WaitForHTTP(context.Background(), fastWaitRetrier(), server.URL, http.StatusOK)
+
+		// then
+		assert.ErrorContains(t, err, "answered with status code 404 instead of 200")
+	})
+	t.Run("should abort on invalid url", func(t *testing.T) {
+		// when
+		err := WaitForHTTP(context.Background(), fastWaitRetrier(), "://invalid", http.StatusOK)
+
+		// then
+		assert.True(t, IsAborted(err))
+	})
+}
@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"context"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// WrapFunc returns a function of the same type as fn that calls fn with retries according to r. This adds retries
+// to existing functions and method values without writing a wrapper or running a code generator, e.g.
+//
+//	getUser := retry.WrapFunc(retrier, client.GetUser)
+//	user, err := getUser(ctx, "admin")
+//
+// fn must be a function whose last result is an error, which is classified by r; otherwise, WrapFunc panics. If
+// the first parameter of fn is a context.Context, the retry loop runs with it and every attempt receives the
+// context of the attempt instead. The other results are the ones of the last attempt, the error is the one of the
+// retry loop, e.g. an ExhaustedError. If no attempt was made, the other results are zero values.
+//
+// There is no WrapInterface returning a retrying proxy of a whole interface, as reflect cannot create types with
+// methods at runtime. Instead, wrap the methods one by one, e.g. in a struct implementing the interface by calling
+// wrapped method values:
+//
+//	type retryingClient struct {
+//		getUser func(ctx context.Context, name string) (*User, error)
+//	}
+//
+//	func (c retryingClient) GetUser(ctx context.Context, name string) (*User, error) {
+//		return c.getUser(ctx, name)
+//	}
+//
+//	var _ UserClient = retryingClient{getUser: retry.WrapFunc(retrier, client.GetUser)}
+//
+// The calls go through reflection, which adds one to two microseconds and about ten allocations per call. This is
+// negligible for network calls but noticeable in tight loops, where a hand-written wrapper calling Retrier.Do is
+// preferable.
+func WrapFunc[F any](r *Retrier, fn F) F {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumOut() == 0 || fnType.Out(fnType.NumOut()-1) != errorType {
+		panic("retry: WrapFunc requires a function whose last result is an error, got " + fnType.String())
+	}
+	withContext := fnType.NumIn() > 0 && fnType.In(0) == contextType
+
+	wrapped := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		ctx := context.Background()
+		if withContext && !args[0].IsNil() {
+			ctx = args[0].Interface().(context.Context)
+		}
+
+		var results []reflect.Value
+		err := r.Do(ctx, func(ctx context.Context) error {
+			callArgs := args
+			if withContext {
+				callArgs = append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, args[1:]...)
+			}
+			if fnType.IsVariadic() {
+				results = fnValue.CallSlice(callArgs)
+			} else {
+				results = fnValue.Call(callArgs)
+			}
+
+			errValue := results[len(results)-1]
+			if errValue.IsNil() {
+				return nil
+			}
+			return errValue.Interface().(error)
+		})
+
+		if results == nil {
+			results = make([]reflect.Value, fnType.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(fnType.Out(i))
+			}
+		}
+		results[len(results)-1] = reflect.Zero(errorType)
+		if err != nil {
+			results[len(results)-1] = reflect.ValueOf(&err).Elem()
+		}
+		return results
+	})
+	return wrapped.Interface().(F)
+}
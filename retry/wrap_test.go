@@ -0,0 +1,108 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userClient struct {
+	failures int
+	calls    int
+}
+
+func (c *userClient) GetUser(ctx context.Context, name string) (string, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return "", fmt.Errorf("call %d: %w", c.calls, assert.AnError)
+	}
+	return strings.ToUpper(name), nil
+}
+
+func Test_WrapFunc(t *testing.T) {
+	t.Run("should retry method value", func(t *testing.T) {
+		// given
+		client := &userClient{failures: 2}
+		sut := WrapFunc(New(WithBackoff(Constant(0))), client.GetUser)
+
+		// when
+		user, err := sut(context.Background(), "admin")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "ADMIN", user)
+		assert.Equal(t, 3, client.calls)
+	})
+	t.Run("should return results of last attempt and error of loop", func(t *testing.T) {
+		// given
+		sut := WrapFunc(New(WithBackoff(Constant(0)), WithMaxTries(2)), func(a, b int) (int, error) {
+			return a + b, assert.AnError
+		})
+
+		// when
+		sum, err := sut(1, 2)
+
+		// then
+		assert.Equal(t, 3, sum)
+		var exhaustedErr *ExhaustedError
+		require.True(t, errors.As(err, &exhaustedErr))
+		assert.Equal(t, 2, exhaustedErr.Attempts)
+	})
+	t.Run("should pass attempt context", func(t *testing.T) {
+		// given
+		var attempts []int
+		sut := WrapFunc(New(WithBackoff(Constant(0)), WithMaxTries(2)), func(ctx context.Context) error {
+			attempt, _ := AttemptFromContext(ctx)
+			attempts = append(attempts, attempt.Number)
+			return assert.AnError
+		})
+
+		// when
+		_ = sut(context.Background())
+
+		// then
+		assert.Equal(t, []int{1, 2}, attempts)
+	})
+	t.Run("should return zero values without attempt", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		called := false
+		sut := WrapFunc(New(), func(ctx context.Context, names ...string) ([]string, int, error) {
+			called = true
+			return names, len(names), nil
+		})
+
+		// when
+		names, count, err := sut(ctx, "admin")
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, names)
+		assert.Zero(t, count)
+		assert.False(t, called)
+	})
+	t.Run("should call variadic function", func(t *testing.T) {
+		// given
+		sut := WrapFunc(New(), func(names ...string) (string, error) {
+			return strings.Join(names, ","), nil
+		})
+
+		// when
+		joined, err := sut("cas", "ldap")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "cas,ldap", joined)
+	})
+	t.Run("should panic without error result", func(t *testing.T) {
+		assert.PanicsWithValue(t, "retry: WrapFunc requires a function whose last result is an error, got func() int", func() {
+			WrapFunc(New(), func() int { return 0 })
+		})
+	})
+}
@@ -0,0 +1,69 @@
+// Package retryaws classifies errors of the AWS SDK for Go v2, so that calls to AWS services are retried on
+// throttling, server-side failures and request timeouts only.
+package retryaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// throttlingCodes lists the error codes AWS services use to signal throttling.
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// transientCodes lists the error codes AWS services use for temporary server-side failures and timeouts.
+var transientCodes = map[string]bool{
+	"RequestTimeout":          true,
+	"RequestTimeoutException": true,
+	"InternalError":           true,
+	"InternalFailure":         true,
+	"ServiceUnavailable":      true,
+}
+
+// Classifier retries AWS errors according to Classify.
+var Classifier retry.Classifier = retry.ClassifierFunc(Classify)
+
+// Classify retries throttling errors, server-side failures with a 5xx status code and request timeouts. All other
+// errors, especially client errors like missing permissions, abort the retry loop.
+func Classify(err error) retry.Decision {
+	if errors.Is(err, context.Canceled) {
+		return retry.DecisionAbort
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		if throttlingCodes[code] || transientCodes[code] {
+			return retry.DecisionRetry
+		}
+	}
+
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		status := statusErr.HTTPStatusCode()
+		if status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500 {
+			return retry.DecisionRetry
+		}
+	}
+
+	return retry.DecisionAbort
+}
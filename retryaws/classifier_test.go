@@ -0,0 +1,43 @@
+package retryaws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func responseError(status int, err error) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      err,
+	}
+}
+
+func Test_Classify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected retry.Decision
+	}{
+		{name: "throttling", err: &smithy.GenericAPIError{Code: "ThrottlingException"}, expected: retry.DecisionRetry},
+		{name: "wrapped throttling", err: fmt.Errorf("put: %w", responseError(400, &smithy.GenericAPIError{Code: "SlowDown"})), expected: retry.DecisionRetry},
+		{name: "request timeout", err: &smithy.GenericAPIError{Code: "RequestTimeout"}, expected: retry.DecisionRetry},
+		{name: "server error", err: responseError(503, &smithy.GenericAPIError{Code: "Unknown"}), expected: retry.DecisionRetry},
+		{name: "too many requests", err: responseError(429, assert.AnError), expected: retry.DecisionRetry},
+		{name: "access denied", err: responseError(403, &smithy.GenericAPIError{Code: "AccessDenied"}), expected: retry.DecisionAbort},
+		{name: "canceled", err: fmt.Errorf("get: %w", context.Canceled), expected: retry.DecisionAbort},
+		{name: "other", err: assert.AnError, expected: retry.DecisionAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classifier.Classify(tt.err))
+		})
+	}
+}
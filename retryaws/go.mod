@@ -0,0 +1,17 @@
+module github.com/cloudogu/retry-lib/retryaws
+
+go 1.23.1
+
+require (
+	github.com/aws/smithy-go v1.22.0
+	github.com/cloudogu/retry-lib v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/cloudogu/retry-lib => ../
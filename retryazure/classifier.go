@@ -0,0 +1,61 @@
+// Package retryazure classifies errors of the Azure SDK for Go, so that calls to Azure services are retried on
+// throttling, server-side failures and request timeouts only.
+package retryazure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Classifier retries Azure errors according to Classify.
+var Classifier retry.Classifier = retry.ClassifierFunc(Classify)
+
+// Classify retries responses with the status codes 408, 429, 500, 502, 503 and 504. If the response advertises
+// when to retry with one of the headers retry-after-ms, x-ms-retry-after-ms or Retry-After, the next attempt is
+// delayed accordingly. All other errors abort the retry loop.
+func Classify(err error) retry.Decision {
+	if errors.Is(err, context.Canceled) {
+		return retry.DecisionAbort
+	}
+
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return retry.DecisionAbort
+	}
+
+	switch responseErr.StatusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return retry.RetryAfter(retryAfter(responseErr.RawResponse))
+	default:
+		return retry.DecisionAbort
+	}
+}
+
+// retryAfter returns the delay advertised by the response or zero.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	for _, header := range []string{"retry-after-ms", "x-ms-retry-after-ms"} {
+		if millis, err := strconv.Atoi(resp.Header.Get(header)); err == nil && millis > 0 {
+			return time.Duration(millis) * time.Millisecond
+		}
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package retryazure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func responseError(status int, header http.Header) error {
+	return &azcore.ResponseError{StatusCode: status, RawResponse: &http.Response{StatusCode: status, Header: header}}
+}
+
+func Test_Classify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected retry.Decision
+	}{
+		{name: "throttling", err: responseError(429, nil), expected: retry.DecisionRetry},
+		{name: "throttling with delay in ms", err: responseError(429, http.Header{"Retry-After-Ms": {"250"}}), expected: retry.RetryAfter(250 * time.Millisecond)},
+		{name: "unavailable with delay", err: fmt.Errorf("list: %w", responseError(503, http.Header{"Retry-After": {"3"}})), expected: retry.RetryAfter(3 * time.Second)},
+		{name: "timeout", err: responseError(408, nil), expected: retry.DecisionRetry},
+		{name: "conflict", err: responseError(409, nil), expected: retry.DecisionAbort},
+		{name: "without response", err: &azcore.ResponseError{StatusCode: 500}, expected: retry.DecisionRetry},
+		{name: "canceled", err: context.Canceled, expected: retry.DecisionAbort},
+		{name: "other", err: assert.AnError, expected: retry.DecisionAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classifier.Classify(tt.err))
+		})
+	}
+}
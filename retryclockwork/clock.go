@@ -0,0 +1,44 @@
+// Package retryclockwork adapts clocks of github.com/jonboulle/clockwork to the retry package.
+package retryclockwork
+
+import (
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// NewClock adapts a clockwork.Clock to retry.Clock. Passing a clockwork.FakeClock to retry.WithClock lets tests
+// drive backoff delays and limits by advancing the fake clock.
+func NewClock(c clockwork.Clock) retry.Clock {
+	return clockAdapter{clock: c}
+}
+
+type clockAdapter struct {
+	clock clockwork.Clock
+}
+
+// Now returns the current time of the adapted clock.
+func (a clockAdapter) Now() time.Time {
+	return a.clock.Now()
+}
+
+// NewTimer creates a timer of the adapted clock.
+func (a clockAdapter) NewTimer(d time.Duration) retry.Timer {
+	return timerAdapter{timer: a.clock.NewTimer(d)}
+}
+
+type timerAdapter struct {
+	timer clockwork.Timer
+}
+
+// C returns the channel of the adapted timer.
+func (a timerAdapter) C() <-chan time.Time {
+	return a.timer.Chan()
+}
+
+// Stop stops the adapted timer.
+func (a timerAdapter) Stop() bool {
+	return a.timer.Stop()
+}
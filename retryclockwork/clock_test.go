@@ -0,0 +1,38 @@
+package retryclockwork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_NewClock(t *testing.T) {
+	// given
+	fakeClock := clockwork.NewFakeClockAt(time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC))
+	sut := retry.New(retry.WithClock(NewClock(fakeClock)), retry.WithBackoff(retry.Constant(time.Hour)),
+		retry.WithLimit(0), retry.WithMaxTries(2))
+	result := make(chan *retry.Report, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// when
+	go func() {
+		report, _ := sut.DoWithReport(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+		result <- report
+	}()
+	require.NoError(t, fakeClock.BlockUntilContext(ctx, 1))
+	fakeClock.Advance(time.Hour)
+
+	// then
+	report := <-result
+	assert.Len(t, report.Attempts, 2)
+	assert.Equal(t, time.Hour, report.Duration)
+}
@@ -0,0 +1,64 @@
+// Package retrygcp classifies errors of the Google Cloud client libraries, so that calls to Google Cloud services
+// are retried on throttling, server-side failures and request timeouts only.
+package retrygcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/googleapis/gax-go/v2/apierror"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Classifier retries Google Cloud errors according to Classify.
+var Classifier retry.Classifier = retry.ClassifierFunc(Classify)
+
+// Classify retries errors of the REST and gRPC based client libraries that indicate throttling, server-side
+// failures or timeouts, i.e. the HTTP status codes 408, 429 and 5xx as well as the gRPC codes ResourceExhausted,
+// Unavailable, Internal and DeadlineExceeded. All other errors abort the retry loop.
+func Classify(err error) retry.Decision {
+	if errors.Is(err, context.Canceled) {
+		return retry.DecisionAbort
+	}
+
+	var apiErr *apierror.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HTTPCode() > 0 {
+			return classifyHTTP(apiErr.HTTPCode())
+		}
+		return classifyGRPC(apiErr.GRPCStatus().Code())
+	}
+
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return classifyHTTP(googleErr.Code)
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return classifyGRPC(grpcErr.GRPCStatus().Code())
+	}
+
+	return retry.DecisionAbort
+}
+
+func classifyHTTP(code int) retry.Decision {
+	if code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500 {
+		return retry.DecisionRetry
+	}
+	return retry.DecisionAbort
+}
+
+func classifyGRPC(code codes.Code) retry.Decision {
+	switch code {
+	case codes.ResourceExhausted, codes.Unavailable, codes.Internal, codes.DeadlineExceeded:
+		return retry.DecisionRetry
+	default:
+		return retry.DecisionAbort
+	}
+}
@@ -0,0 +1,48 @@
+package retrygcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func apiError(t *testing.T, err error) error {
+	t.Helper()
+	apiErr, ok := apierror.FromError(err)
+	require.True(t, ok)
+	return apiErr
+}
+
+func Test_Classify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected retry.Decision
+	}{
+		{name: "rest throttling", err: &googleapi.Error{Code: 429}, expected: retry.DecisionRetry},
+		{name: "rest server error", err: fmt.Errorf("upload: %w", &googleapi.Error{Code: 503}), expected: retry.DecisionRetry},
+		{name: "rest timeout", err: &googleapi.Error{Code: 408}, expected: retry.DecisionRetry},
+		{name: "rest forbidden", err: &googleapi.Error{Code: 403}, expected: retry.DecisionAbort},
+		{name: "api error over rest", err: apiError(t, &googleapi.Error{Code: 502}), expected: retry.DecisionRetry},
+		{name: "api error over grpc", err: apiError(t, status.Error(codes.ResourceExhausted, "quota")), expected: retry.DecisionRetry},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "down"), expected: retry.DecisionRetry},
+		{name: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "slow"), expected: retry.DecisionRetry},
+		{name: "grpc not found", err: status.Error(codes.NotFound, "missing"), expected: retry.DecisionAbort},
+		{name: "canceled", err: context.Canceled, expected: retry.DecisionAbort},
+		{name: "other", err: assert.AnError, expected: retry.DecisionAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classifier.Classify(tt.err))
+		})
+	}
+}
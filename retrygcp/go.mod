@@ -0,0 +1,22 @@
+module github.com/cloudogu/retry-lib/retrygcp
+
+go 1.23.1
+
+require (
+	github.com/cloudogu/retry-lib v0.0.0-00010101000000-000000000000
+	github.com/googleapis/gax-go/v2 v2.13.0
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/api v0.188.0
+	google.golang.org/grpc v1.67.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/cloudogu/retry-lib => ../
@@ -0,0 +1,42 @@
+package retrygrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// BreakerInterceptor returns an interceptor guarding every target with its own circuit breaker. Chained after
+// UnaryClientInterceptor, calls to a target whose breaker is open fail immediately instead of burning their retry
+// budget:
+//
+//	grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(), BreakerInterceptor(breakers))
+//
+// Calls failing with codes.Unavailable count as failures of the target.
+func BreakerInterceptor(breakers *retry.BreakerRegistry) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		breaker := breakers.Get(target(cc))
+		if err := breaker.Allow(); err != nil {
+			return retry.Abort(status.Error(codes.Unavailable, err.Error()))
+		}
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if status.Code(err) == codes.Unavailable {
+			breaker.Failure()
+		} else {
+			breaker.Success()
+		}
+		return err
+	}
+}
+
+func target(cc *grpc.ClientConn) string {
+	if cc == nil {
+		return ""
+	}
+	return cc.Target()
+}
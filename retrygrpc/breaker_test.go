@@ -0,0 +1,49 @@
+package retrygrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_BreakerInterceptor(t *testing.T) {
+	t.Run("should stop retrying a dead target", func(t *testing.T) {
+		// given
+		calls := 0
+		breakers := retry.NewBreakerRegistry(2, time.Minute)
+		retrying := UnaryClientInterceptor(retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(5))
+		breaking := BreakerInterceptor(breakers)
+		invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return breaking(ctx, method, req, reply, cc, failingInvoker(100, codes.Unavailable, &calls), opts...)
+		}
+
+		// when
+		err := retrying(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+
+		// then
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.True(t, retry.IsAborted(err))
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, retry.BreakerOpen, breakers.Get("").State())
+	})
+	t.Run("should not count other errors as failures", func(t *testing.T) {
+		// given
+		calls := 0
+		breakers := retry.NewBreakerRegistry(1, time.Minute)
+		sut := BreakerInterceptor(breakers)
+
+		// when
+		err := sut(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(1, codes.InvalidArgument, &calls))
+
+		// then
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, retry.BreakerClosed, breakers.Get("").State())
+	})
+}
@@ -0,0 +1,33 @@
+// Package retrygrpc retries gRPC calls with the Retrier of github.com/cloudogu/retry-lib/retry.
+package retrygrpc
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// DefaultRetriableCodes contains the status codes which indicate a temporary problem of the server.
+var DefaultRetriableCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+
+// IsRetriable returns true if the error carries one of the DefaultRetriableCodes.
+func IsRetriable(err error) bool {
+	return slices.Contains(DefaultRetriableCodes, status.Code(err))
+}
+
+// UnaryClientInterceptor returns an interceptor retrying unary calls. By default, calls are retried if IsRetriable
+// returns true, opts may override this with retry.WithRetriable.
+func UnaryClientInterceptor(opts ...retry.Option) grpc.UnaryClientInterceptor {
+	retrier := retry.New(append([]retry.Option{retry.WithRetriable(IsRetriable)}, opts...)...)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		return retrier.Do(ctx, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package retrygrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func failingInvoker(failures int, code codes.Code, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "failed")
+		}
+		return nil
+	}
+}
+
+func Test_UnaryClientInterceptor(t *testing.T) {
+	fastRetry := []retry.Option{retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(3)}
+
+	t.Run("should retry unavailable server", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := UnaryClientInterceptor(fastRetry...)
+
+		// when
+		err := sut(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(2, codes.Unavailable, &calls))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("should not retry other codes", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := UnaryClientInterceptor(fastRetry...)
+
+		// when
+		err := sut(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(2, codes.InvalidArgument, &calls))
+
+		// then
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should keep status code when exhausted", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := UnaryClientInterceptor(fastRetry...)
+
+		// when
+		err := sut(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(10, codes.Unavailable, &calls))
+
+		// then
+		var exhaustedErr *retry.ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("should apply custom retriable function", func(t *testing.T) {
+		// given
+		calls := 0
+		sut := UnaryClientInterceptor(append(fastRetry, retry.WithRetriable(retry.AlwaysRetryFunc))...)
+
+		// when
+		err := sut(context.Background(), "/svc/Method", nil, nil, nil, failingInvoker(2, codes.Internal, &calls))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+}
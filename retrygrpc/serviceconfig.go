@@ -0,0 +1,132 @@
+package retrygrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// serviceConfig is the part of a gRPC service config describing retries, see
+// https://github.com/grpc/proposal/blob/master/A6-client-retries.md.
+type serviceConfig struct {
+	MethodConfig []methodConfig `json:"methodConfig"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int          `json:"maxAttempts"`
+	InitialBackoff       string       `json:"initialBackoff"`
+	MaxBackoff           string       `json:"maxBackoff"`
+	BackoffMultiplier    float64      `json:"backoffMultiplier"`
+	RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+}
+
+// maxAttemptsLimit is the upper bound gRPC applies to maxAttempts of retry policies.
+const maxAttemptsLimit = 5
+
+// options converts the policy to retry options. Like gRPC, it rejects maxAttempts below 2 and caps it at 5.
+func (p *retryPolicy) options() ([]retry.Option, error) {
+	initialBackoff, err := time.ParseDuration(p.InitialBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initialBackoff: %w", err)
+	}
+	maxBackoff, err := time.ParseDuration(p.MaxBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxBackoff: %w", err)
+	}
+	if p.MaxAttempts < 2 {
+		return nil, fmt.Errorf("invalid maxAttempts %d: must be at least 2", p.MaxAttempts)
+	}
+
+	retryableCodes := p.RetryableStatusCodes
+	return []retry.Option{
+		retry.WithMaxTries(min(p.MaxAttempts, maxAttemptsLimit)),
+		retry.WithInitialDelay(initialBackoff),
+		retry.WithBackoffFactor(p.BackoffMultiplier),
+		retry.WithMaxDelay(maxBackoff),
+		retry.WithRetriable(func(err error) bool {
+			return slices.Contains(retryableCodes, status.Code(err))
+		}),
+	}, nil
+}
+
+// UnaryClientInterceptorFromServiceConfig returns an interceptor retrying unary calls according to the retry policies
+// of the given gRPC service config JSON, e.g. one published by the service via service discovery:
+//
+//	{"methodConfig": [{
+//		"name": [{"service": "ces.Registry"}],
+//		"retryPolicy": {
+//			"maxAttempts": 4,
+//			"initialBackoff": "0.1s",
+//			"maxBackoff": "1s",
+//			"backoffMultiplier": 2,
+//			"retryableStatusCodes": ["UNAVAILABLE"]
+//		}
+//	}]}
+//
+// Like gRPC, the interceptor applies the policy of the method's own entry, of the service's entry or of the default
+// entry without service, in that order, and does not retry methods without a policy. maxAttempts above 5 are capped
+// at 5 like gRPC does. opts are applied before the policy, e.g. to set a limit or jitter, as gRPC itself randomizes
+// its delays.
+func UnaryClientInterceptorFromServiceConfig(config string, opts ...retry.Option) (grpc.UnaryClientInterceptor, error) {
+	var parsed serviceConfig
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse service config: %w", err)
+	}
+
+	retriers := map[methodName]*retry.Retrier{}
+	for i, methodConfig := range parsed.MethodConfig {
+		var retrier *retry.Retrier
+		if methodConfig.RetryPolicy != nil {
+			policyOpts, err := methodConfig.RetryPolicy.options()
+			if err != nil {
+				return nil, fmt.Errorf("invalid retry policy of method config %d: %w", i, err)
+			}
+			retrier = retry.New(append(opts[:len(opts):len(opts)], policyOpts...)...)
+		}
+		for _, name := range methodConfig.Name {
+			if _, ok := retriers[name]; !ok {
+				retriers[name] = retrier
+			}
+		}
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		retrier := lookupRetrier(retriers, method)
+		if retrier == nil {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		return retrier.Do(ctx, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		})
+	}, nil
+}
+
+// lookupRetrier returns the retrier for the full method name, e.g. /ces.Registry/Get, with the precedence of gRPC.
+func lookupRetrier(retriers map[methodName]*retry.Retrier, fullMethod string) *retry.Retrier {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	for _, name := range []methodName{{Service: service, Method: method}, {Service: service}, {}} {
+		if retrier, ok := retriers[name]; ok {
+			return retrier
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package retrygrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testServiceConfig = `{"methodConfig": [
+	{
+		"name": [{"service": "ces.Registry", "method": "Delete"}]
+	},
+	{
+		"name": [{"service": "ces.Registry"}],
+		"retryPolicy": {
+			"maxAttempts": 3,
+			"initialBackoff": "0.001s",
+			"maxBackoff": "0.002s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE", 8]
+		}
+	},
+	{
+		"name": [{}],
+		"retryPolicy": {
+			"maxAttempts": 2,
+			"initialBackoff": "0.001s",
+			"maxBackoff": "0.001s",
+			"backoffMultiplier": 1,
+			"retryableStatusCodes": ["INTERNAL"]
+		}
+	}
+]}`
+
+func Test_UnaryClientInterceptorFromServiceConfig(t *testing.T) {
+	sut, err := UnaryClientInterceptorFromServiceConfig(testServiceConfig)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		method        string
+		code          codes.Code
+		expectedCalls int
+	}{
+		{name: "service policy", method: "/ces.Registry/Get", code: codes.Unavailable, expectedCalls: 3},
+		{name: "numeric status code", method: "/ces.Registry/Get", code: codes.ResourceExhausted, expectedCalls: 3},
+		{name: "non-retryable status code", method: "/ces.Registry/Get", code: codes.Internal, expectedCalls: 1},
+		{name: "method without policy", method: "/ces.Registry/Delete", code: codes.Unavailable, expectedCalls: 1},
+		{name: "default policy", method: "/ces.Dogus/List", code: codes.Internal, expectedCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			calls := 0
+
+			// when
+			err := sut(context.Background(), tt.method, nil, nil, nil, failingInvoker(10, tt.code, &calls))
+
+			// then
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func Test_UnaryClientInterceptorFromServiceConfig_invalid(t *testing.T) {
+	t.Run("should fail on invalid JSON", func(t *testing.T) {
+		_, err := UnaryClientInterceptorFromServiceConfig("{")
+		assert.ErrorContains(t, err, "failed to parse service config")
+	})
+	t.Run("should fail on invalid backoff", func(t *testing.T) {
+		_, err := UnaryClientInterceptorFromServiceConfig(`{"methodConfig": [{"name": [{}], "retryPolicy": {"initialBackoff": "soon", "maxBackoff": "1s"}}]}`)
+		assert.ErrorContains(t, err, "invalid retry policy of method config 0: invalid initialBackoff")
+	})
+	t.Run("should fail on less than two attempts", func(t *testing.T) {
+		_, err := UnaryClientInterceptorFromServiceConfig(`{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 1, "initialBackoff": "1s", "maxBackoff": "1s"}}]}`)
+		assert.ErrorContains(t, err, "invalid retry policy of method config 0: invalid maxAttempts 1")
+	})
+}
+
+func Test_UnaryClientInterceptorFromServiceConfig_maxAttempts(t *testing.T) {
+	// given
+	config := `{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 100, "initialBackoff": "0.001s", "maxBackoff": "0.001s", "backoffMultiplier": 1, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`
+	sut, err := UnaryClientInterceptorFromServiceConfig(config)
+	require.NoError(t, err)
+	calls := 0
+
+	// when
+	err = sut(context.Background(), "/ces.Registry/Get", nil, nil, nil, failingInvoker(10, codes.Unavailable, &calls))
+
+	// then
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 5, calls)
+}
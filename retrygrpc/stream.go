@@ -0,0 +1,79 @@
+package retrygrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Receiver is the receiving side of a server stream. It is implemented by grpc.ServerStreamingClient.
+type Receiver[T any] interface {
+	Recv() (*T, error)
+}
+
+// brokenStreamError signals that a stream broke after it delivered messages.
+type brokenStreamError struct {
+	err error
+}
+
+func (e *brokenStreamError) Error() string {
+	return e.err.Error()
+}
+
+// ResumeStream consumes a server stream, e.g. of a watch-style method, and re-establishes it once it breaks. open
+// opens the stream and receives the last message passed to handle, or nil for the first stream, so that it can
+// request to resume after it, e.g. with the resource version or offset of the message. This way, consumers neither
+// receive messages twice nor miss any on reconnects.
+//
+// Broken streams are reopened if IsRetriable returns true for the error of open or Recv. opts configure the backoff
+// and limits, which start over once a reopened stream delivered a message. ResumeStream returns nil once the server
+// ends the stream, the error of handle if it fails and the error of the retry loop otherwise.
+func ResumeStream[T any](ctx context.Context, open func(ctx context.Context, last *T) (Receiver[T], error), handle func(ctx context.Context, msg *T) error, opts ...retry.Option) error {
+	retrier := retry.New(append(opts[:len(opts):len(opts)], retry.WithRetriable(IsRetriable))...)
+
+	var last *T
+	for {
+		err := retrier.Do(ctx, func(ctx context.Context) error {
+			stream, err := open(ctx, last)
+			if err != nil {
+				return err
+			}
+
+			progressed := false
+			for {
+				msg, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					if progressed && IsRetriable(err) {
+						return retry.Abort(&brokenStreamError{err: err})
+					}
+					return err
+				}
+
+				if err := handle(ctx, msg); err != nil {
+					return retry.Abort(err)
+				}
+				last = msg
+				progressed = true
+			}
+		})
+
+		var brokenErr *brokenStreamError
+		if !errors.As(err, &brokenErr) {
+			return unwrapAbort(err)
+		}
+	}
+}
+
+// unwrapAbort returns the error wrapped with retry.Abort, if any, so that the caller sees the error of handle.
+func unwrapAbort(err error) error {
+	var abortErr *retry.AbortError
+	if errors.As(err, &abortErr) {
+		return abortErr.Err
+	}
+	return err
+}
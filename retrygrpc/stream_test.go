@@ -0,0 +1,130 @@
+package retrygrpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+type event struct {
+	Offset int
+}
+
+// receiverMock delivers the events and ends with err.
+type receiverMock struct {
+	events []*event
+	err    error
+}
+
+func (r *receiverMock) Recv() (*event, error) {
+	if len(r.events) == 0 {
+		return nil, r.err
+	}
+	next := r.events[0]
+	r.events = r.events[1:]
+	return next, nil
+}
+
+// eventsAfter returns the events of the log following last.
+func eventsAfter(log []*event, last *event) []*event {
+	if last == nil {
+		return log
+	}
+	return log[last.Offset+1:]
+}
+
+func Test_ResumeStream(t *testing.T) {
+	fastRetry := []retry.Option{retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(2)}
+	log := []*event{{Offset: 0}, {Offset: 1}, {Offset: 2}, {Offset: 3}}
+
+	t.Run("should resume broken stream after last handled message", func(t *testing.T) {
+		// given
+		var resumedAfter []*event
+		opens := 0
+		open := func(ctx context.Context, last *event) (Receiver[event], error) {
+			opens++
+			resumedAfter = append(resumedAfter, last)
+			events := eventsAfter(log, last)
+			switch opens {
+			case 1:
+				return &receiverMock{events: events[:2], err: status.Error(codes.Unavailable, "connection reset")}, nil
+			case 2:
+				return nil, status.Error(codes.Unavailable, "connection refused")
+			case 3:
+				return &receiverMock{events: events[:1], err: status.Error(codes.Unavailable, "connection reset")}, nil
+			default:
+				return &receiverMock{events: events, err: io.EOF}, nil
+			}
+		}
+		var handled []int
+
+		// when
+		err := ResumeStream(context.Background(), open, func(ctx context.Context, msg *event) error {
+			handled = append(handled, msg.Offset)
+			return nil
+		}, fastRetry...)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2, 3}, handled)
+		assert.Equal(t, []*event{nil, log[1], log[1], log[2]}, resumedAfter)
+	})
+	t.Run("should give up if stream cannot be reopened", func(t *testing.T) {
+		// given
+		opens := 0
+		open := func(ctx context.Context, last *event) (Receiver[event], error) {
+			opens++
+			return nil, status.Error(codes.Unavailable, "connection refused")
+		}
+
+		// when
+		err := ResumeStream(context.Background(), open, func(ctx context.Context, msg *event) error {
+			return nil
+		}, fastRetry...)
+
+		// then
+		var exhaustedErr *retry.ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 2, opens)
+	})
+	t.Run("should not reopen on non-retriable error", func(t *testing.T) {
+		// given
+		opens := 0
+		open := func(ctx context.Context, last *event) (Receiver[event], error) {
+			opens++
+			return &receiverMock{events: log[:1], err: status.Error(codes.PermissionDenied, "denied")}, nil
+		}
+
+		// when
+		err := ResumeStream(context.Background(), open, func(ctx context.Context, msg *event) error {
+			return nil
+		}, fastRetry...)
+
+		// then
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, 1, opens)
+	})
+	t.Run("should return error of handle", func(t *testing.T) {
+		// given
+		open := func(ctx context.Context, last *event) (Receiver[event], error) {
+			return &receiverMock{events: log, err: io.EOF}, nil
+		}
+
+		// when
+		err := ResumeStream(context.Background(), open, func(ctx context.Context, msg *event) error {
+			return assert.AnError
+		}, fastRetry...)
+
+		// then
+		assert.Same(t, assert.AnError, err)
+	})
+}
@@ -0,0 +1,46 @@
+package retryhttp
+
+import (
+	"net/http"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// BreakerTransport is an http.RoundTripper guarding every host with its own circuit breaker. Used as base of a
+// Transport, requests to a host whose breaker is open fail immediately instead of burning their retry budget.
+type BreakerTransport struct {
+	base     http.RoundTripper
+	breakers *retry.BreakerRegistry
+}
+
+// NewBreakerTransport creates a BreakerTransport executing requests with base, which defaults to
+// http.DefaultTransport. Network errors and 5xx responses count as failures of the host.
+func NewBreakerTransport(base http.RoundTripper, breakers *retry.BreakerRegistry) *BreakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &BreakerTransport{base: base, breakers: breakers}
+}
+
+// RoundTrip executes the request unless the breaker of its host is open. In that case, an error wrapping
+// retry.ErrBreakerOpen is returned which stops a surrounding Transport from retrying.
+func (t *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := t.breakers.Get(req.URL.Host)
+	if err := breaker.Allow(); err != nil {
+		return nil, retry.Abort(err)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		breaker.Failure()
+	} else {
+		breaker.Success()
+	}
+	return resp, err
+}
+
+// CloseIdleConnections closes the idle connections of the base transport if it supports it.
+func (t *BreakerTransport) CloseIdleConnections() {
+	closeIdleConnections(t.base)
+}
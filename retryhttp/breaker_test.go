@@ -0,0 +1,51 @@
+package retryhttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_BreakerTransport_RoundTrip(t *testing.T) {
+	t.Run("should stop retrying a dead host", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 100, http.StatusServiceUnavailable)
+		breakers := retry.NewBreakerRegistry(2, time.Minute)
+		retryOpts := []retry.Option{retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(5)}
+		client := &http.Client{Transport: NewTransport(NewBreakerTransport(nil, breakers), retryOpts...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+		secondResp, secondErr := client.Get(server.URL)
+
+		// then
+		if resp != nil {
+			resp.Body.Close()
+		}
+		require.Error(t, err)
+		assert.ErrorIs(t, err, retry.ErrBreakerOpen)
+		assert.ErrorIs(t, secondErr, retry.ErrBreakerOpen)
+		assert.Nil(t, secondResp)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should keep breaker closed for healthy host", func(t *testing.T) {
+		// given
+		server, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		breakers := retry.NewBreakerRegistry(2, time.Minute)
+		client := &http.Client{Transport: NewTransport(NewBreakerTransport(nil, breakers), fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, retry.BreakerClosed, breakers.Get(resp.Request.URL.Host).State())
+	})
+}
@@ -0,0 +1,134 @@
+package retryhttp
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AttemptHeader is set by the Transport on every retried request and contains the number of the attempt. Servers
+// use it to tell retries apart from first attempts, see RetryBudget.
+const AttemptHeader = "X-Retry-Attempt"
+
+// RetryBudget limits how many retried requests a single client may send within a time window. It is the
+// server-side counterpart of the Transport: clients exceeding their budget receive a 429 response whose
+// Retry-After header tells them when their budget is replenished.
+type RetryBudget struct {
+	limit     int
+	window    time.Duration
+	clientKey func(*http.Request) string
+
+	mu        sync.Mutex
+	clients   map[string]*budgetWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type budgetWindow struct {
+	start   time.Time
+	retries int
+}
+
+// NewRetryBudget creates a RetryBudget allowing limit retries per client within each window. clientKey identifies
+// the client of a request and defaults to its remote IP address.
+func NewRetryBudget(limit int, window time.Duration, clientKey func(*http.Request) string) *RetryBudget {
+	if clientKey == nil {
+		clientKey = remoteIP
+	}
+
+	return &RetryBudget{
+		limit:     limit,
+		window:    window,
+		clientKey: clientKey,
+		clients:   map[string]*budgetWindow{},
+		now:       time.Now,
+	}
+}
+
+// Handler wraps next and rejects retried requests of clients that exceeded their budget.
+func (b *RetryBudget) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AttemptHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if retryAfter, ok := b.take(b.clientKey(r)); !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			http.Error(w, "retry budget exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take consumes one retry of the client's budget. If the budget is exhausted, it returns the time until the
+// budget is replenished.
+func (b *RetryBudget) take(client string) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.sweep(now)
+
+	current, ok := b.clients[client]
+	if !ok || now.Sub(current.start) >= b.window {
+		current = &budgetWindow{start: now}
+		b.clients[client] = current
+	}
+
+	if current.retries >= b.limit {
+		return current.start.Add(b.window).Sub(now), false
+	}
+	current.retries++
+	return 0, true
+}
+
+// Pressure returns the share of the retry budget consumed by the clients within their current window, averaged
+// over all clients that sent retries during that window. It reaches 1 once all of them exhausted their budget.
+func (b *RetryBudget) Pressure() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	retries, clients := 0, 0
+	for _, current := range b.clients {
+		if now.Sub(current.start) < b.window {
+			retries += current.retries
+			clients++
+		}
+	}
+	if clients == 0 {
+		return 0
+	}
+	if b.limit <= 0 {
+		return 1
+	}
+	return float64(retries) / float64(clients*b.limit)
+}
+
+// sweep removes clients whose window has passed so that the budget does not grow without bounds.
+func (b *RetryBudget) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < b.window {
+		return
+	}
+
+	b.lastSweep = now
+	for client, current := range b.clients {
+		if now.Sub(current.start) >= b.window {
+			delete(b.clients, client)
+		}
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
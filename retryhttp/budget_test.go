@@ -0,0 +1,143 @@
+package retryhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_RetryBudget_Handler(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func(handler http.Handler, remoteAddr string, retry bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if retry {
+			req.Header.Set(AttemptHeader, "2")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("should always pass first attempts", func(t *testing.T) {
+		// given
+		sut := NewRetryBudget(0, time.Minute, nil).Handler(okHandler)
+
+		// when
+		actual := serve(sut, "10.0.0.1:1234", false)
+
+		// then
+		assert.Equal(t, http.StatusOK, actual.Code)
+	})
+	t.Run("should reject retries exceeding the budget", func(t *testing.T) {
+		// given
+		budget := NewRetryBudget(2, time.Minute, nil)
+		now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		budget.now = func() time.Time { return now }
+		sut := budget.Handler(okHandler)
+
+		// when
+		first := serve(sut, "10.0.0.1:1234", true)
+		second := serve(sut, "10.0.0.1:5678", true)
+		now = now.Add(20 * time.Second)
+		third := serve(sut, "10.0.0.1:1234", true)
+		otherClient := serve(sut, "10.0.0.2:1234", true)
+
+		// then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, http.StatusTooManyRequests, third.Code)
+		assert.Equal(t, "40", third.Header().Get("Retry-After"))
+		assert.Equal(t, http.StatusOK, otherClient.Code)
+	})
+	t.Run("should replenish budget after window", func(t *testing.T) {
+		// given
+		budget := NewRetryBudget(1, time.Minute, nil)
+		now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		budget.now = func() time.Time { return now }
+		sut := budget.Handler(okHandler)
+
+		// when
+		first := serve(sut, "10.0.0.1:1234", true)
+		rejected := serve(sut, "10.0.0.1:1234", true)
+		now = now.Add(time.Minute)
+		replenished := serve(sut, "10.0.0.1:1234", true)
+
+		// then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+		assert.Equal(t, http.StatusOK, replenished.Code)
+	})
+	t.Run("should use custom client key", func(t *testing.T) {
+		// given
+		sut := NewRetryBudget(1, time.Minute, func(r *http.Request) string {
+			return "everyone"
+		}).Handler(okHandler)
+
+		// when
+		first := serve(sut, "10.0.0.1:1234", true)
+		second := serve(sut, "10.0.0.2:1234", true)
+
+		// then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	})
+	t.Run("should forget expired clients", func(t *testing.T) {
+		// given
+		budget := NewRetryBudget(1, time.Minute, nil)
+		now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		budget.now = func() time.Time { return now }
+		sut := budget.Handler(okHandler)
+		serve(sut, "10.0.0.1:1234", true)
+
+		// when
+		now = now.Add(2 * time.Minute)
+		serve(sut, "10.0.0.2:1234", true)
+
+		// then
+		assert.Len(t, budget.clients, 1)
+	})
+}
+
+func Test_RetryBudget_Pressure(t *testing.T) {
+	t.Run("should average consumed budget of clients", func(t *testing.T) {
+		// given
+		sut := NewRetryBudget(4, time.Minute, nil)
+		now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		sut.now = func() time.Time { return now }
+
+		// when
+		before := sut.Pressure()
+		for range 3 {
+			sut.take("10.0.0.1")
+		}
+		sut.take("10.0.0.2")
+
+		// then
+		assert.Equal(t, 0.0, before)
+		assert.Equal(t, 0.5, sut.Pressure())
+	})
+	t.Run("should ignore passed windows", func(t *testing.T) {
+		// given
+		sut := NewRetryBudget(1, time.Minute, nil)
+		now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+		sut.now = func() time.Time { return now }
+		sut.take("10.0.0.1")
+
+		// when
+		now = now.Add(time.Minute)
+
+		// then
+		assert.Equal(t, 0.0, sut.Pressure())
+	})
+	t.Run("should implement pressure source", func(t *testing.T) {
+		var _ retry.PressureSource = NewRetryBudget(1, time.Minute, nil)
+	})
+}
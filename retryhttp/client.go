@@ -0,0 +1,59 @@
+package retryhttp
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Client is a drop-in replacement for http.Client retrying requests with a Transport. Request bodies that cannot be
+// obtained again via http.Request.GetBody, e.g. streams passed to Post, are buffered by the Transport up to its
+// MaxBufferedBody, so that requests with such bodies are retried as well.
+//
+// The fields of http.Client like Timeout and Jar are available as usual. Timeout applies to the whole retry loop.
+type Client struct {
+	http.Client
+}
+
+// NewClient creates a Client whose Transport is created by NewTransport with base and opts.
+func NewClient(base http.RoundTripper, opts ...retry.Option) *Client {
+	return &Client{Client: http.Client{Transport: NewTransport(base, opts...)}}
+}
+
+// Get issues a GET request to the given URL like http.Client.Get.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Head issues a HEAD request to the given URL like http.Client.Head.
+func (c *Client) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST request to the given URL like http.Client.Post. As POST is not idempotent, the request is sent
+// once unless the Transport's RetryMethod allows retrying it. Use Do with a request carrying an Idempotency-Key
+// header to retry it, in which case a plain io.Reader as body is buffered up to MaxBufferedBody.
+func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// PostForm issues a POST request with the URL-encoded data like http.Client.PostForm. It is sent once like Post.
+func (c *Client) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.Post(url, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
@@ -0,0 +1,151 @@
+package retryhttp
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamReader hides the type of the wrapped reader, so that http.NewRequest cannot set GetBody.
+type streamReader struct {
+	io.Reader
+}
+
+// requestRecorder answers every request with 200 and records it.
+type requestRecorder struct {
+	requests []*http.Request
+}
+
+func (r *requestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func Test_Client(t *testing.T) {
+	t.Run("should retry stream body", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		sut := NewClient(nil, fastRetry...)
+		req, err := http.NewRequest(http.MethodPut, server.URL, streamReader{strings.NewReader("payload")})
+		require.NoError(t, err)
+
+		// when
+		resp, err := sut.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:payload", string(body))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should retry get", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 2, http.StatusBadGateway)
+		sut := NewClient(nil, fastRetry...)
+
+		// when
+		resp, err := sut.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("should retry form post", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		sut := NewClient(nil, fastRetry...)
+		sut.Transport.(*Transport).RetryMethod = func(*http.Request) bool { return true }
+
+		// when
+		resp, err := sut.PostForm(server.URL, url.Values{"user": {"admin"}})
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:user=admin", string(body))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should post stream body", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 0, http.StatusOK)
+		sut := NewClient(nil, fastRetry...)
+
+		// when
+		resp, err := sut.Post(server.URL, "text/plain", streamReader{strings.NewReader("payload")})
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:payload", string(body))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should not buffer body of post that is not retried", func(t *testing.T) {
+		// given
+		recorder := &requestRecorder{}
+		sut := NewClient(recorder, fastRetry...)
+		body := streamReader{strings.NewReader("payload")}
+
+		// when
+		resp, err := sut.Post("http://localhost", "text/plain", body)
+
+		// then
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Len(t, recorder.requests, 1)
+		assert.Nil(t, recorder.requests[0].GetBody)
+		sent, _ := io.ReadAll(recorder.requests[0].Body)
+		assert.Equal(t, "payload", string(sent))
+	})
+	t.Run("should retry head", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		sut := NewClient(nil, fastRetry...)
+
+		// when
+		resp, err := sut.Head(server.URL)
+
+		// then
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should not buffer stream body above limit", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		sut := NewClient(nil, fastRetry...)
+		sut.Transport.(*Transport).MaxBufferedBody = 4
+		req, err := http.NewRequest(http.MethodPut, server.URL, streamReader{strings.NewReader("payload")})
+		require.NoError(t, err)
+
+		// when
+		_, err = sut.Do(req)
+
+		// then
+		var bodyErr *BodyNotRewindableError
+		assert.ErrorAs(t, err, &bodyErr)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should fail on unreadable body", func(t *testing.T) {
+		// given
+		sut := NewClient(nil, fastRetry...)
+		req, err := http.NewRequest(http.MethodPut, "http://localhost", streamReader{iotest.ErrReader(assert.AnError)})
+		require.NoError(t, err)
+
+		// when
+		_, err = sut.Do(req)
+
+		// then
+		assert.ErrorContains(t, err, "failed to buffer request body")
+	})
+}
@@ -0,0 +1,84 @@
+package retryhttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostCooldowns remembers until when retries to a host have to be delayed because the host asked for it.
+type hostCooldowns struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+func newHostCooldowns() *hostCooldowns {
+	return &hostCooldowns{until: map[string]time.Time{}}
+}
+
+// observe starts or extends the cooldown of the host if resp asks clients to back off via Retry-After.
+func (c *hostCooldowns) observe(host string, resp *http.Response) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return
+	}
+
+	now := time.Now()
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until := now.Add(retryAfter)
+	if until.After(c.until[host]) {
+		c.until[host] = until
+	}
+}
+
+// wait blocks until the cooldown of the host has passed. It returns false if ctx is done before.
+func (c *hostCooldowns) wait(ctx context.Context, host string) bool {
+	c.mu.Lock()
+	until, ok := c.until[host]
+	if ok && !time.Now().Before(until) {
+		delete(c.until, host)
+		ok = false
+	}
+	c.mu.Unlock()
+	if !ok {
+		return true
+	}
+
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which contains either the delay in seconds or a date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
@@ -0,0 +1,103 @@
+package retryhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 11, 15, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "abc", ok: false},
+		{value: "-1", ok: false},
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: " 3 ", expected: 3 * time.Second, ok: true},
+		{value: "Fri, 15 Nov 2024 08:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Fri, 15 Nov 2024 07:00:00 GMT", expected: 0, ok: true},
+	}
+	for _, tt := range tests {
+		actual, ok := parseRetryAfter(tt.value, now)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.expected, actual, tt.value)
+	}
+}
+
+func Test_hostCooldowns(t *testing.T) {
+	t.Run("should delay other requests to the same host", func(t *testing.T) {
+		// given
+		sut := newHostCooldowns()
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}}
+		sut.observe("example.com", resp)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		sameHost := sut.wait(ctx, "example.com")
+		otherHost := sut.wait(context.Background(), "example.org")
+
+		// then
+		assert.False(t, sameHost)
+		assert.True(t, otherHost)
+	})
+	t.Run("should ignore other status codes", func(t *testing.T) {
+		// given
+		sut := newHostCooldowns()
+		resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{"Retry-After": {"60"}}}
+
+		// when
+		sut.observe("example.com", resp)
+
+		// then
+		assert.Empty(t, sut.until)
+	})
+	t.Run("should forget passed cooldowns", func(t *testing.T) {
+		// given
+		sut := newHostCooldowns()
+		sut.until["example.com"] = time.Now().Add(-time.Second)
+
+		// when
+		ok := sut.wait(context.Background(), "example.com")
+
+		// then
+		assert.True(t, ok)
+		assert.Empty(t, sut.until)
+	})
+}
+
+func Test_Transport_cooldown(t *testing.T) {
+	// given
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+	start := time.Now()
+
+	// when
+	resp, err := client.Get(server.URL)
+
+	// then
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
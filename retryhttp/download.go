@@ -0,0 +1,196 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// ChecksumError is returned if a downloaded file does not match its expected checksum.
+type ChecksumError struct {
+	Expected []byte
+	Actual   []byte
+}
+
+// Error returns the error's string representation.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch: expected %x but got %x", e.Expected, e.Actual)
+}
+
+// Checksum describes the expected checksum of a download.
+type Checksum struct {
+	// Hash computes the checksum, e.g. sha256.New().
+	Hash hash.Hash
+	// Sum is the expected result of Hash.
+	Sum []byte
+}
+
+// Downloader retries HTTP downloads. An interrupted download resumes from the last received byte with a Range
+// request instead of starting over.
+type Downloader struct {
+	client  *http.Client
+	retrier *retry.Retrier
+}
+
+// NewDownloader creates a Downloader sending requests with client, which defaults to http.DefaultClient. opts
+// configure the retry behaviour.
+func NewDownloader(client *http.Client, opts ...retry.Option) *Downloader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Downloader{client: client, retrier: retry.New(opts...)}
+}
+
+// Download writes the content of url to w and returns the number of written bytes. Network errors and retriable
+// status codes resume the download, other status codes and errors writing to w end it. If checksum is not nil, the
+// content is verified at the end and a ChecksumError is returned on a mismatch.
+//
+// The number of written bytes is reported with retry.ReportProgress, so that a callback set with retry.WithProgress
+// sees the progress across all attempts. Servers not supporting Range requests answer with the full content, whose
+// already written prefix is skipped then. If the content changed in between according to its ETag, the download
+// fails as w cannot be rewound.
+func (d *Downloader) Download(ctx context.Context, url string, w io.Writer, checksum *Checksum) (int64, error) {
+	if checksum != nil {
+		checksum.Hash.Reset()
+		w = io.MultiWriter(w, checksum.Hash)
+	}
+
+	state := &downloadState{w: w}
+	err := d.retrier.Do(ctx, func(ctx context.Context) error {
+		return d.attempt(ctx, url, state)
+	})
+	if err != nil {
+		return state.written, err
+	}
+
+	if checksum != nil {
+		actual := checksum.Hash.Sum(nil)
+		if !bytes.Equal(actual, checksum.Sum) {
+			return state.written, &ChecksumError{Expected: checksum.Sum, Actual: actual}
+		}
+	}
+	return state.written, nil
+}
+
+// downloadState persists the progress of a download across attempts.
+type downloadState struct {
+	w       io.Writer
+	written int64
+	total   int64
+	etag    string
+}
+
+func (d *Downloader) attempt(ctx context.Context, url string, state *downloadState) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return retry.Abort(err)
+	}
+	if state.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", state.written))
+		if state.etag != "" {
+			req.Header.Set("If-Range", state.etag)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	skip := int64(0)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if state.written > 0 && state.etag != "" && resp.Header.Get("ETag") != state.etag {
+			return retry.Abort(errors.New("content changed while resuming the download"))
+		}
+		skip = state.written
+		state.total = max(resp.ContentLength, 0)
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != state.written {
+			return retry.Abort(fmt.Errorf("unexpected content range %q", resp.Header.Get("Content-Range")))
+		}
+		state.total = total
+	case http.StatusRequestedRangeNotSatisfiable:
+		if state.written > 0 {
+			// Everything was received already, the connection merely broke before the end was noticed.
+			return nil
+		}
+		return retry.Abort(&StatusError{StatusCode: resp.StatusCode})
+	default:
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		if DefaultShouldRetry(resp, nil) {
+			return statusErr
+		}
+		return retry.Abort(statusErr)
+	}
+	if state.etag == "" {
+		state.etag = resp.Header.Get("ETag")
+	}
+
+	if _, err := io.CopyN(io.Discard, resp.Body, skip); err != nil {
+		return err
+	}
+	return copyBody(ctx, state, resp.Body)
+}
+
+// copyBody writes body to the download's writer and reports the progress. Read errors are retried, write errors
+// abort the download.
+func copyBody(ctx context.Context, state *downloadState, body io.Reader) error {
+	buf := make([]byte, 32<<10)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if _, err := state.w.Write(buf[:n]); err != nil {
+				return retry.Abort(err)
+			}
+			state.written += int64(n)
+			retry.ReportProgress(ctx, retry.Progress{Bytes: state.written, TotalBytes: state.total})
+		}
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// parseContentRange returns the first byte position and the total size of a Content-Range header like
+// "bytes 100-199/200". The total is zero if the header marks it as unknown.
+func parseContentRange(value string) (int64, int64, bool) {
+	rangeSpec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	start, rest, ok := strings.Cut(rangeSpec, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	position, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	_, size, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return position, 0, true
+	}
+	total, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return position, total, true
+}
@@ -0,0 +1,162 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+var downloadContent = bytes.Repeat([]byte("0123456789"), 10_000)
+
+// newInterruptingServer serves downloadContent but breaks off the first `interruptions` responses halfway.
+func newInterruptingServer(t *testing.T, interruptions int32, supportRange bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	calls := &atomic.Int32{}
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if calls.Add(1) <= interruptions {
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+			_, _ = w.Write(downloadContent[:len(downloadContent)/2])
+			return
+		}
+		if !supportRange {
+			r.Header.Del("Range")
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(downloadContent))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func Test_Downloader_Download(t *testing.T) {
+	t.Run("should resume interrupted download", func(t *testing.T) {
+		// given
+		server, ranges := newInterruptingServer(t, 2, true)
+		sut := NewDownloader(nil, fastRetry...)
+		buf := &bytes.Buffer{}
+		sum := sha256.Sum256(downloadContent)
+
+		// when
+		written, err := sut.Download(context.Background(), server.URL, buf, &Checksum{Hash: sha256.New(), Sum: sum[:]})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(downloadContent)), written)
+		assert.Equal(t, downloadContent, buf.Bytes())
+		half := strconv.Itoa(len(downloadContent) / 2)
+		assert.Equal(t, []string{"", "bytes=" + half + "-", "bytes=" + half + "-"}, *ranges)
+	})
+	t.Run("should report progress across attempts", func(t *testing.T) {
+		// given
+		server, _ := newInterruptingServer(t, 1, true)
+		var last retry.Progress
+		decreased := false
+		sut := NewDownloader(nil, append(fastRetry, retry.WithProgress(func(progress retry.Progress) {
+			decreased = decreased || progress.Bytes < last.Bytes
+			last = progress
+		}))...)
+
+		// when
+		_, err := sut.Download(context.Background(), server.URL, &bytes.Buffer{}, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, decreased)
+		assert.Equal(t, int64(len(downloadContent)), last.Bytes)
+		assert.Equal(t, 100.0, last.Percentage())
+	})
+	t.Run("should skip received prefix without range support", func(t *testing.T) {
+		// given
+		server, _ := newInterruptingServer(t, 1, false)
+		sut := NewDownloader(nil, fastRetry...)
+		buf := &bytes.Buffer{}
+
+		// when
+		_, err := sut.Download(context.Background(), server.URL, buf, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, downloadContent, buf.Bytes())
+	})
+	t.Run("should fail on checksum mismatch", func(t *testing.T) {
+		// given
+		server, _ := newInterruptingServer(t, 0, true)
+		sut := NewDownloader(nil, fastRetry...)
+
+		// when
+		_, err := sut.Download(context.Background(), server.URL, &bytes.Buffer{}, &Checksum{Hash: sha256.New(), Sum: []byte{1}})
+
+		// then
+		var checksumErr *ChecksumError
+		require.ErrorAs(t, err, &checksumErr)
+		assert.Equal(t, []byte{1}, checksumErr.Expected)
+	})
+	t.Run("should not retry client errors", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusNotFound)
+		sut := NewDownloader(nil, fastRetry...)
+
+		// when
+		_, err := sut.Download(context.Background(), server.URL, &bytes.Buffer{}, nil)
+
+		// then
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should fail if content changed", func(t *testing.T) {
+		// given
+		calls := &atomic.Int32{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+			w.Header().Set("ETag", `"v`+strconv.Itoa(int(calls.Add(1)))+`"`)
+			_, _ = w.Write(downloadContent[:len(downloadContent)/2])
+		}))
+		defer server.Close()
+		sut := NewDownloader(nil, fastRetry...)
+
+		// when
+		_, err := sut.Download(context.Background(), server.URL, &bytes.Buffer{}, nil)
+
+		// then
+		assert.ErrorContains(t, err, "content changed")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func Test_parseContentRange(t *testing.T) {
+	tests := []struct {
+		value         string
+		expectedStart int64
+		expectedTotal int64
+		ok            bool
+	}{
+		{value: "bytes 100-199/200", expectedStart: 100, expectedTotal: 200, ok: true},
+		{value: "bytes 0-0/*", expectedStart: 0, expectedTotal: 0, ok: true},
+		{value: "items 1-2/3", ok: false},
+		{value: "bytes x-1/2", ok: false},
+		{value: "bytes 1-2", ok: false},
+		{value: "bytes 1-2/x", ok: false},
+		{value: "", ok: false},
+	}
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.value)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.expectedStart, start, tt.value)
+		assert.Equal(t, tt.expectedTotal, total, tt.value)
+	}
+}
@@ -0,0 +1,95 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// ociDefaults are applied to an OCI registry transport before the options of the caller.
+var ociDefaults = []retry.Option{
+	retry.WithInitialDelay(time.Second),
+	retry.WithBackoffFactor(2),
+	retry.WithMaxDelay(30 * time.Second),
+	retry.WithLimit(5 * time.Minute),
+}
+
+// NewOCITransport creates a Transport preset for OCI registries serving container images and Helm charts. It retries
+// according to OCIShouldRetry and waits as long as OCIRateLimitDelay demands before retrying a rate limited request.
+// If the registry asks to wait longer than the limit of the retry loop, the rate limited response is returned right
+// away. opts are applied after the preset's backoff, which starts at one second and gives up after five minutes.
+func NewOCITransport(base http.RoundTripper, opts ...retry.Option) *Transport {
+	transport := NewTransport(base, append(ociDefaults[:len(ociDefaults):len(ociDefaults)], opts...)...)
+	transport.ShouldRetry = OCIShouldRetry
+	transport.RetryDelay = OCIRateLimitDelay
+	return transport
+}
+
+// OCIShouldRetry retries the status code 429, all 5xx status codes, timeouts and connections reset or closed by the
+// registry while the response is transferred. Other network errors like refused connections or failed name
+// resolution are not retried as they usually point to a misconfigured registry address.
+func OCIShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || retry.IsAborted(err) {
+			return false
+		}
+
+		var netErr net.Error
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNABORTED) ||
+			errors.Is(err, syscall.EPIPE) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			(errors.As(err, &netErr) && netErr.Timeout())
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// OCIRateLimitDelay returns how long to wait before retrying a response with the status code 429 according to the
+// rate limit headers of Docker Hub and the IETF draft they follow. If RateLimit-Remaining reports the quota to be
+// used up, the delay is taken from RateLimit-Reset or, if missing, from the window of the quota like in
+// "0;w=21600". Zero is returned if the response does not state a delay.
+func OCIRateLimitDelay(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+
+	remaining, window, ok := parseRateLimit(resp.Header.Get("RateLimit-Remaining"))
+	if !ok || remaining > 0 {
+		return 0
+	}
+	if reset, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("RateLimit-Reset"))); err == nil && reset > 0 {
+		return time.Duration(reset) * time.Second
+	}
+	return window
+}
+
+// parseRateLimit parses a rate limit header like "76;w=21600" into the quota and the window it applies to.
+func parseRateLimit(value string) (int, time.Duration, bool) {
+	quota, params, _ := strings.Cut(value, ";")
+	count, err := strconv.Atoi(strings.TrimSpace(quota))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	var window time.Duration
+	for _, param := range strings.Split(params, ";") {
+		name, seconds, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || name != "w" {
+			continue
+		}
+		if parsed, err := strconv.Atoi(seconds); err == nil && parsed > 0 {
+			window = time.Duration(parsed) * time.Second
+		}
+	}
+	return count, window, true
+}
@@ -0,0 +1,103 @@
+package retryhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_OCIShouldRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), expected: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "timeout", err: context.DeadlineExceeded, expected: true},
+		{name: "connection refused", err: syscall.ECONNREFUSED, expected: false},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "aborted", err: retry.Abort(io.EOF), expected: false},
+		{name: "rate limited", resp: &http.Response{StatusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "server error", resp: &http.Response{StatusCode: http.StatusInternalServerError}, expected: true},
+		{name: "gateway timeout", resp: &http.Response{StatusCode: http.StatusGatewayTimeout}, expected: true},
+		{name: "unauthorized", resp: &http.Response{StatusCode: http.StatusUnauthorized}, expected: false},
+		{name: "not found", resp: &http.Response{StatusCode: http.StatusNotFound}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, OCIShouldRetry(tt.resp, tt.err))
+		})
+	}
+}
+
+func Test_OCIRateLimitDelay(t *testing.T) {
+	rateLimited := func(header http.Header) *http.Response {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: header}
+	}
+
+	assert.Equal(t, 6*time.Hour, OCIRateLimitDelay(rateLimited(http.Header{"Ratelimit-Remaining": {"0;w=21600"}})))
+	assert.Equal(t, 30*time.Second, OCIRateLimitDelay(rateLimited(http.Header{"Ratelimit-Remaining": {"0;w=21600"}, "Ratelimit-Reset": {"30"}})))
+	assert.Zero(t, OCIRateLimitDelay(rateLimited(http.Header{"Ratelimit-Remaining": {"5;w=21600"}})))
+	assert.Zero(t, OCIRateLimitDelay(rateLimited(http.Header{"Ratelimit-Remaining": {"invalid"}})))
+	assert.Zero(t, OCIRateLimitDelay(rateLimited(http.Header{})))
+	assert.Zero(t, OCIRateLimitDelay(&http.Response{StatusCode: http.StatusOK, Header: http.Header{"Ratelimit-Remaining": {"0;w=60"}}}))
+}
+
+func Test_NewOCITransport(t *testing.T) {
+	t.Run("should retry after rate limit reset", func(t *testing.T) {
+		// given
+		calls := &atomic.Int32{}
+		var retriedAt time.Time
+		start := time.Now()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("RateLimit-Remaining", "0;w=21600")
+				w.Header().Set("RateLimit-Reset", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			retriedAt = time.Now()
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewOCITransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.GreaterOrEqual(t, retriedAt.Sub(start), time.Second)
+	})
+	t.Run("should give up if rate limit lasts beyond limit", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("RateLimit-Remaining", "0;w=21600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewOCITransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+}
@@ -0,0 +1,54 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// identityProviderDefaults are applied to an identity provider transport before the options of the caller. They
+// bridge the restart of a single instance of the identity provider during a rolling update.
+var identityProviderDefaults = []retry.Option{
+	retry.WithInitialDelay(time.Second),
+	retry.WithBackoffFactor(2),
+	retry.WithMaxDelay(15 * time.Second),
+	retry.WithLimit(2 * time.Minute),
+}
+
+// TokenSource returns a valid access token for the admin API of an identity provider.
+type TokenSource func(ctx context.Context) (string, error)
+
+// NewIdentityProviderTransport creates a Transport preset for the admin APIs of identity providers like Keycloak. It
+// retries according to IdentityProviderShouldRetry and, if tokens is not nil, requests a fresh access token before
+// every retry and sends it as bearer token, as the token of the first attempt may have expired meanwhile. opts are
+// applied after the preset's backoff, which starts at one second and gives up after two minutes.
+func NewIdentityProviderTransport(base http.RoundTripper, tokens TokenSource, opts ...retry.Option) *Transport {
+	transport := NewTransport(base, append(identityProviderDefaults[:len(identityProviderDefaults):len(identityProviderDefaults)], opts...)...)
+	transport.ShouldRetry = IdentityProviderShouldRetry
+	if tokens != nil {
+		transport.BeforeRetry = func(req *http.Request) error {
+			token, err := tokens(req.Context())
+			if err != nil {
+				return fmt.Errorf("failed to refresh access token: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		}
+	}
+	return transport
+}
+
+// IdentityProviderShouldRetry retries network errors and the status codes 502 and 503 which proxies answer with while
+// the identity provider restarts. All other status codes, especially authentication and authorization errors, are
+// not retried.
+func IdentityProviderShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !retry.IsAborted(err)
+	}
+
+	return resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable
+}
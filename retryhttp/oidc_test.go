@@ -0,0 +1,87 @@
+package retryhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_IdentityProviderShouldRetry(t *testing.T) {
+	assert.True(t, IdentityProviderShouldRetry(nil, assert.AnError))
+	assert.False(t, IdentityProviderShouldRetry(nil, context.Canceled))
+	assert.False(t, IdentityProviderShouldRetry(nil, retry.Abort(assert.AnError)))
+	assert.True(t, IdentityProviderShouldRetry(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.True(t, IdentityProviderShouldRetry(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.False(t, IdentityProviderShouldRetry(&http.Response{StatusCode: http.StatusUnauthorized}, nil))
+	assert.False(t, IdentityProviderShouldRetry(&http.Response{StatusCode: http.StatusForbidden}, nil))
+	assert.False(t, IdentityProviderShouldRetry(&http.Response{StatusCode: http.StatusInternalServerError}, nil))
+}
+
+func Test_NewIdentityProviderTransport(t *testing.T) {
+	t.Run("should refresh token before retry", func(t *testing.T) {
+		// given
+		var tokens []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokens = append(tokens, r.Header.Get("Authorization"))
+			if len(tokens) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+		refreshes := &atomic.Int32{}
+		source := func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("token-%d", refreshes.Add(1)), nil
+		}
+		client := &http.Client{Transport: NewIdentityProviderTransport(nil, source, fastRetry...)}
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer initial")
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Bearer initial", "Bearer token-1", "Bearer token-2"}, tokens)
+	})
+	t.Run("should return error of token source", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusBadGateway)
+		source := func(ctx context.Context) (string, error) {
+			return "", assert.AnError
+		}
+		client := &http.Client{Transport: NewIdentityProviderTransport(nil, source, fastRetry...)}
+
+		// when
+		_, err := client.Get(server.URL)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to refresh access token")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should not retry authentication errors", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusUnauthorized)
+		client := &http.Client{Transport: NewIdentityProviderTransport(nil, nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -0,0 +1,334 @@
+// Package retryhttp retries HTTP requests with the Retrier of github.com/cloudogu/retry-lib/retry.
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// maxDrainBytes limits how much of a discarded response body is read to allow reusing its connection.
+const maxDrainBytes = 64 << 10
+
+// IdempotencyKeyHeader carries a unique key of a request that lets the server recognize and drop its repetitions.
+// Requests with this header are retried regardless of their method by default.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IsIdempotent reports whether req may be sent repeatedly without changing the result, i.e. whether its method is
+// GET, HEAD, OPTIONS, TRACE, PUT or DELETE or it carries the IdempotencyKeyHeader. Hence, POST and PATCH requests are
+// only retried with an idempotency key by default.
+func IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get(IdempotencyKeyHeader) != ""
+	}
+}
+
+// anyMethod retries requests regardless of their method.
+func anyMethod(*http.Request) bool {
+	return true
+}
+
+// DefaultMaxBufferedBody is the size up to which the Transport buffers request bodies without GetBody by default.
+const DefaultMaxBufferedBody = 1 << 20
+
+// BodyNotRewindableError is returned instead of the outcome of a request that should be retried but whose body can
+// neither be obtained again via http.Request.GetBody nor was small enough to be buffered.
+type BodyNotRewindableError struct {
+	// Err is the error of the attempt, a StatusError if the server responded with a retriable status code.
+	Err error
+}
+
+// Error returns the error's string representation.
+func (e *BodyNotRewindableError) Error() string {
+	return fmt.Sprintf("cannot retry request as its body cannot be rewound, set GetBody or increase MaxBufferedBody: %s", e.Err)
+}
+
+// Unwrap returns the error of the attempt.
+func (e *BodyNotRewindableError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError marks a response whose status code was considered retriable.
+type StatusError struct {
+	StatusCode int
+}
+
+// Error returns the error's string representation.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received retriable status code %d", e.StatusCode)
+}
+
+// DefaultShouldRetry retries network errors and responses indicating a temporary server-side problem. Errors
+// created with retry.Abort are not retried.
+func DefaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !retry.IsAborted(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Transport is an http.RoundTripper that retries requests. Requests with a body are retried if the body can be
+// obtained again via http.Request.GetBody, which http.NewRequest sets for bytes.Buffer, bytes.Reader and
+// strings.Reader. Other bodies are buffered in memory up to MaxBufferedBody. Larger ones are sent only once; if the
+// outcome should be retried, a BodyNotRewindableError is returned instead.
+//
+// If a host responds with 429 or 503 and a Retry-After header, all retries to this host are delayed until the
+// advertised time has passed, not only the ones of the current request.
+type Transport struct {
+	// ReResolve drops the idle connections of the base transport before retrying a request that failed with a
+	// network error. The retry then dials anew and thereby resolves the host again, so that it reaches the new
+	// endpoints after a failover instead of reusing pooled connections to addresses that are gone.
+	ReResolve bool
+	// RetryMethod decides whether a request may be retried at all, usually based on its method. Requests it rejects
+	// are sent only once. It defaults to IsIdempotent.
+	RetryMethod func(req *http.Request) bool
+	// MethodPolicies maps HTTP methods to options overriding the policy of the Transport for requests with this
+	// method, e.g. to retry GET requests longer than DELETE requests. The options must not replace the classifier.
+	MethodPolicies map[string][]retry.Option
+	// ShouldRetry decides whether the outcome of an attempt is retried. It defaults to DefaultShouldRetry.
+	ShouldRetry func(resp *http.Response, err error) bool
+	// BeforeRetry is called with the copy of the request before it is sent again, e.g. to replace an expired access
+	// token. If it returns an error, the request is not retried and the error is returned to the caller.
+	BeforeRetry func(req *http.Request) error
+	// RetryDelay returns how long to wait before retrying the given response. If it is nil or returns zero, the
+	// backoff of the Transport applies.
+	RetryDelay func(resp *http.Response) time.Duration
+	// MaxBufferedBody is the size up to which request bodies without GetBody are buffered in memory to retry them. It
+	// defaults to DefaultMaxBufferedBody, a negative value disables the buffering.
+	MaxBufferedBody int64
+
+	base      http.RoundTripper
+	retrier   *retry.Retrier
+	cooldowns *hostCooldowns
+}
+
+// NewTransport creates a Transport executing the requests with base, which defaults to http.DefaultTransport.
+// Only idempotent requests according to IsIdempotent are retried unless RetryMethod is set. Whether their outcomes
+// are retried is decided by DefaultShouldRetry unless ShouldRetry is set, opts configure the retry behaviour
+// otherwise.
+func NewTransport(base http.RoundTripper, opts ...retry.Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		RetryMethod: IsIdempotent,
+		ShouldRetry: DefaultShouldRetry,
+		base:        base,
+		retrier:     retry.New(append(opts[:len(opts):len(opts)], retry.WithClassifier(retry.ClassifierFunc(classifyAttempt)))...),
+		cooldowns:   newHostCooldowns(),
+	}
+}
+
+// attemptError carries the outcome of an attempt that should be retried.
+type attemptError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *attemptError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attemptError) Unwrap() error {
+	return e.err
+}
+
+// classifyAttempt retries attempt errors after the delay they carry, if any.
+func classifyAttempt(err error) retry.Decision {
+	var attemptErr *attemptError
+	if !errors.As(err, &attemptErr) {
+		return retry.DecisionAbort
+	}
+	return retry.RetryAfter(attemptErr.delay)
+}
+
+// RoundTrip executes the request until it yields a non-retriable outcome or the retries are exhausted. In the
+// latter case, the last response or error is returned.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryMethod()(req) {
+		return t.base.RoundTrip(req)
+	}
+	if !rewindable(req) {
+		buffered, ok, err := t.bufferBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return t.roundTripOnce(buffered)
+		}
+		req = buffered
+	}
+
+	var resp *http.Response
+	var respErr error
+	attempt := 0
+	err := t.retrier.Do(req.Context(), func(ctx context.Context) error {
+		attempt++
+		if resp != nil {
+			discard(resp)
+		}
+		if respErr != nil && t.ReResolve {
+			t.CloseIdleConnections()
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err == nil && attempt > 1 && t.BeforeRetry != nil {
+			err = t.BeforeRetry(attemptReq)
+		}
+		if err != nil {
+			resp, respErr = nil, err
+			return nil
+		}
+		if attempt > 1 && !t.cooldowns.wait(ctx, req.URL.Host) {
+			resp, respErr = nil, ctx.Err()
+			return nil
+		}
+
+		resp, respErr = t.base.RoundTrip(attemptReq)
+		t.cooldowns.observe(req.URL.Host, resp)
+		if !t.shouldRetry()(resp, respErr) {
+			return nil
+		}
+		if respErr != nil {
+			return &attemptError{err: respErr}
+		}
+		attemptErr := &attemptError{err: &StatusError{StatusCode: resp.StatusCode}}
+		if t.RetryDelay != nil {
+			attemptErr.delay = t.RetryDelay(resp)
+		}
+		return attemptErr
+	}, t.MethodPolicies[req.Method]...)
+
+	var exhaustedErr *retry.ExhaustedError
+	if err != nil && !errors.As(err, &exhaustedErr) {
+		if resp != nil {
+			discard(resp)
+		}
+		return nil, err
+	}
+
+	return resp, respErr
+}
+
+// roundTripOnce sends a request whose body cannot be rewound and reports outcomes that should have been retried.
+func (t *Transport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	t.cooldowns.observe(req.URL.Host, resp)
+	if !t.shouldRetry()(resp, err) {
+		return resp, err
+	}
+	if err != nil {
+		return nil, &BodyNotRewindableError{Err: err}
+	}
+	discard(resp)
+	return nil, &BodyNotRewindableError{Err: &StatusError{StatusCode: resp.StatusCode}}
+}
+
+// bufferBody returns a copy of req whose body is buffered in memory and can be obtained again via GetBody. If the
+// body exceeds MaxBufferedBody, the copy contains the original body instead and false is returned.
+func (t *Transport) bufferBody(req *http.Request) (*http.Request, bool, error) {
+	limit := t.MaxBufferedBody
+	if limit == 0 {
+		limit = DefaultMaxBufferedBody
+	}
+	if limit < 0 || req.ContentLength > limit {
+		return req, false, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, false, fmt.Errorf("failed to buffer request body: %w", err)
+	}
+
+	clone := req.Clone(req.Context())
+	if int64(len(body)) > limit {
+		clone.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		return clone, false, nil
+	}
+
+	_ = req.Body.Close()
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return clone, true, nil
+}
+
+func (t *Transport) retryMethod() func(req *http.Request) bool {
+	if t.RetryMethod == nil {
+		return IsIdempotent
+	}
+	return t.RetryMethod
+}
+
+func (t *Transport) shouldRetry() func(resp *http.Response, err error) bool {
+	if t.ShouldRetry == nil {
+		return DefaultShouldRetry
+	}
+	return t.ShouldRetry
+}
+
+// CloseIdleConnections closes the idle connections of the base transport if it supports it.
+func (t *Transport) CloseIdleConnections() {
+	closeIdleConnections(t.base)
+}
+
+func closeIdleConnections(transport http.RoundTripper) {
+	if closer, ok := transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the request to send for the given attempt. The first attempt uses the original request, every
+// further attempt a copy with a fresh body and the AttemptHeader set.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 {
+		return req, nil
+	}
+
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		clone.Body = body
+	}
+	clone.Header.Set(AttemptHeader, strconv.Itoa(attempt))
+	return clone, nil
+}
+
+// discard drains and closes the body of a response that is not handed out to the caller.
+func discard(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	_ = resp.Body.Close()
+}
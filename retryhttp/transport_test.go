@@ -0,0 +1,406 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+var fastRetry = []retry.Option{retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(3)}
+
+func newFlakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write(append([]byte("ok:"), body...))
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+func Test_Transport_RoundTrip(t *testing.T) {
+	t.Run("should retry retriable status codes", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("should mark retried requests", func(t *testing.T) {
+		// given
+		var attempts []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts = append(attempts, r.Header.Get(AttemptHeader))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, []string{"", "2", "3"}, attempts)
+	})
+	t.Run("should resend body", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusBadGateway)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:payload", string(body))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should return last response when exhausted", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("should keep classification under context overrides", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+		ctx := retry.ContextWithPolicy(context.Background(), retry.WithRetriable(retry.TestableRetryFunc), retry.WithMaxTries(2))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should not retry non-retriable status codes", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusNotFound)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should retry according to custom ShouldRetry", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusNotFound)
+		transport := NewTransport(nil, fastRetry...)
+		transport.ShouldRetry = func(resp *http.Response, err error) bool {
+			return err == nil && resp.StatusCode == http.StatusNotFound
+		}
+		client := &http.Client{Transport: transport}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should retry buffered body without GetBody", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+		req, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		require.NoError(t, err)
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:payload", string(body))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should refuse to retry body exceeding buffer", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		transport := NewTransport(nil, fastRetry...)
+		transport.MaxBufferedBody = 4
+		client := &http.Client{Transport: transport}
+		req, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		require.NoError(t, err)
+
+		// when
+		_, err = client.Do(req)
+
+		// then
+		var bodyErr *BodyNotRewindableError
+		require.True(t, errors.As(err, &bodyErr))
+		var statusErr *StatusError
+		require.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should send body exceeding buffer completely", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 0, http.StatusOK)
+		transport := NewTransport(nil, fastRetry...)
+		transport.MaxBufferedBody = 4
+		client := &http.Client{Transport: transport}
+		req, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		require.NoError(t, err)
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "ok:payload", string(body))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should not buffer if disabled", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		transport := NewTransport(nil, fastRetry...)
+		transport.MaxBufferedBody = -1
+		client := &http.Client{Transport: transport}
+		req, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		require.NoError(t, err)
+
+		// when
+		_, err = client.Do(req)
+
+		// then
+		var bodyErr *BodyNotRewindableError
+		assert.True(t, errors.As(err, &bodyErr))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should return last network error when exhausted", func(t *testing.T) {
+		// given
+		server, _ := newFlakyServer(t, 0, http.StatusOK)
+		server.Close()
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		_, err := client.Get(server.URL)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "connect")
+	})
+	t.Run("should stop on canceled context", func(t *testing.T) {
+		// given
+		server, _ := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, retry.WithBackoff(retry.Constant(time.Hour)), retry.WithLimit(0))}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		// when
+		_, err = client.Do(req)
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// closeRecordingTransport fails the first requests with a network error and records closed idle connections.
+type closeRecordingTransport struct {
+	failures   int
+	calls      int
+	closeCalls int
+}
+
+func (c *closeRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, assert.AnError
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func (c *closeRecordingTransport) CloseIdleConnections() {
+	c.closeCalls++
+}
+
+func Test_Transport_ReResolve(t *testing.T) {
+	t.Run("should drop idle connections before retrying network errors", func(t *testing.T) {
+		// given
+		base := &closeRecordingTransport{failures: 2}
+		sut := NewTransport(base, fastRetry...)
+		sut.ReResolve = true
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+
+		// when
+		resp, err := sut.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, base.closeCalls)
+	})
+	t.Run("should keep idle connections by default", func(t *testing.T) {
+		// given
+		base := &closeRecordingTransport{failures: 2}
+		sut := NewTransport(base, fastRetry...)
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+
+		// when
+		_, err := sut.RoundTrip(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, base.closeCalls)
+	})
+	t.Run("should forward through breaker transport", func(t *testing.T) {
+		// given
+		base := &closeRecordingTransport{}
+		sut := NewTransport(NewBreakerTransport(base, retry.NewBreakerRegistry(5, time.Minute)))
+
+		// when
+		sut.CloseIdleConnections()
+
+		// then
+		assert.Equal(t, 1, base.closeCalls)
+	})
+}
+
+func Test_Transport_methods(t *testing.T) {
+	t.Run("should not retry post without idempotency key", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("should retry post with idempotency key", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable)
+		client := &http.Client{Transport: NewTransport(nil, fastRetry...)}
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "8e03978e-40d5-43e8-bc93-6894a57f9324")
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should apply method policy", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		transport := NewTransport(nil, fastRetry...)
+		transport.MethodPolicies = map[string][]retry.Option{http.MethodDelete: {retry.WithMaxTries(5)}}
+		client := &http.Client{Transport: transport}
+		req, err := http.NewRequest(http.MethodDelete, server.URL, nil)
+		require.NoError(t, err)
+
+		// when
+		resp, err := client.Do(req)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, int32(5), calls.Load())
+	})
+	t.Run("should use custom retry method", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable)
+		transport := NewTransport(nil, fastRetry...)
+		transport.RetryMethod = func(req *http.Request) bool { return req.Method != http.MethodGet }
+		client := &http.Client{Transport: transport}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func Test_IsIdempotent(t *testing.T) {
+	tests := []struct {
+		method   string
+		key      string
+		expected bool
+	}{
+		{method: http.MethodGet, expected: true},
+		{method: http.MethodHead, expected: true},
+		{method: http.MethodOptions, expected: true},
+		{method: http.MethodPut, expected: true},
+		{method: http.MethodDelete, expected: true},
+		{method: http.MethodPost, expected: false},
+		{method: http.MethodPatch, expected: false},
+		{method: http.MethodPost, key: "key", expected: true},
+		{method: http.MethodPatch, key: "key", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.key, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "http://example.com", nil)
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			assert.Equal(t, tt.expected, IsIdempotent(req))
+		})
+	}
+}
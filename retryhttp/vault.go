@@ -0,0 +1,89 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// maxPeekBytes limits how much of a response body is inspected by the presets.
+const maxPeekBytes = 4 << 10
+
+// vaultTransientMessages are contained in the errors Vault reports while it is sealed or a standby node cannot
+// forward the request to the active node, e.g. during a leader election.
+var vaultTransientMessages = [][]byte{
+	[]byte("Vault is sealed"),
+	[]byte("standby"),
+	[]byte("local node not active"),
+}
+
+// vaultDefaults are applied to a Vault transport before the options of the caller.
+var vaultDefaults = []retry.Option{
+	retry.WithInitialDelay(500 * time.Millisecond),
+	retry.WithBackoffFactor(2),
+	retry.WithMaxDelay(10 * time.Second),
+	retry.WithLimit(time.Minute),
+}
+
+// NewVaultTransport creates a Transport preset for HashiCorp Vault and compatible secret stores. It retries according
+// to VaultShouldRetry with an exponential backoff starting at 500 milliseconds and capped at 10 seconds for up to one
+// minute. Requests are retried regardless of their method, as Vault handles POST requests like PUT requests. opts are
+// applied afterward and may change these defaults.
+func NewVaultTransport(base http.RoundTripper, opts ...retry.Option) *Transport {
+	transport := NewTransport(base, append(vaultDefaults[:len(vaultDefaults):len(vaultDefaults)], opts...)...)
+	transport.ShouldRetry = VaultShouldRetry
+	transport.RetryMethod = anyMethod
+	return transport
+}
+
+// VaultShouldRetry retries network errors, the status codes 429, 500, 502 and 503 as well as errors reporting that
+// Vault is sealed or the node is in standby. A 403 is never retried as missing permissions do not resolve by
+// themselves.
+func VaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !retry.IsAborted(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusForbidden:
+		return false
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable:
+		return true
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return false
+	}
+
+	body := peekBody(resp)
+	for _, message := range vaultTransientMessages {
+		if bytes.Contains(body, message) {
+			return true
+		}
+	}
+	return false
+}
+
+// peekBody returns the beginning of the response body without consuming it. The body of resp is replaced by one
+// that yields the peeked bytes again, followed by the rest of the original body.
+func peekBody(resp *http.Response) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+
+	peeked, _ := io.ReadAll(io.LimitReader(resp.Body, maxPeekBytes))
+	resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(peeked), resp.Body), Closer: resp.Body}
+	return peeked
+}
+
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,100 @@
+package retryhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func vaultResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func Test_VaultShouldRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{name: "network error", err: assert.AnError, expected: true},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "aborted", err: retry.Abort(assert.AnError), expected: false},
+		{name: "rate limited", resp: vaultResponse(http.StatusTooManyRequests, ""), expected: true},
+		{name: "internal error", resp: vaultResponse(http.StatusInternalServerError, ""), expected: true},
+		{name: "bad gateway", resp: vaultResponse(http.StatusBadGateway, ""), expected: true},
+		{name: "sealed", resp: vaultResponse(http.StatusServiceUnavailable, `{"errors":["Vault is sealed"]}`), expected: true},
+		{name: "standby", resp: vaultResponse(http.StatusBadRequest, `{"errors":["local node not active but active cluster node not found"]}`), expected: true},
+		{name: "permission denied", resp: vaultResponse(http.StatusForbidden, `{"errors":["permission denied"]}`), expected: false},
+		{name: "not found", resp: vaultResponse(http.StatusNotFound, `{"errors":[]}`), expected: false},
+		{name: "gateway timeout", resp: vaultResponse(http.StatusGatewayTimeout, ""), expected: false},
+		{name: "success", resp: vaultResponse(http.StatusOK, "standby"), expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, VaultShouldRetry(tt.resp, tt.err))
+		})
+	}
+
+	t.Run("should preserve inspected body", func(t *testing.T) {
+		// given
+		resp := vaultResponse(http.StatusBadRequest, `{"errors":["invalid request"]}`)
+
+		// when
+		VaultShouldRetry(resp, nil)
+
+		// then
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"errors":["invalid request"]}`, string(body))
+	})
+}
+
+func Test_NewVaultTransport(t *testing.T) {
+	t.Run("should retry sealed vault", func(t *testing.T) {
+		// given
+		calls := &atomic.Int32{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"errors":["Vault is sealed"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: NewVaultTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("should abort on forbidden", func(t *testing.T) {
+		// given
+		server, calls := newFlakyServer(t, 1, http.StatusForbidden)
+		client := &http.Client{Transport: NewVaultTransport(nil, fastRetry...)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
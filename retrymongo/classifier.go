@@ -0,0 +1,50 @@
+// Package retrymongo classifies the errors of MongoDB transactions according to the error labels set by the server
+// and the official driver go.mongodb.org/mongo-driver. The labels are read with the HasErrorLabel method of the
+// driver's errors, so that the driver needs not be imported.
+package retrymongo
+
+import (
+	"errors"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+const (
+	// TransientTransactionError labels errors after which the whole transaction may be retried from the start.
+	TransientTransactionError = "TransientTransactionError"
+	// UnknownTransactionCommitResult labels errors after which the commit of the transaction may be retried.
+	UnknownTransactionCommitResult = "UnknownTransactionCommitResult"
+)
+
+// labeled is implemented by the errors of the MongoDB driver like mongo.CommandError and mongo.WriteException.
+type labeled interface {
+	HasErrorLabel(label string) bool
+}
+
+// TransactionClassifier retries the errors of IsTransientTransactionError. It is meant for retry loops spanning the
+// whole transaction, i.e. starting it, executing its operations and committing it.
+var TransactionClassifier retry.Classifier = retry.Predicate(IsTransientTransactionError)
+
+// CommitClassifier retries the errors of IsUnknownTransactionCommitResult. It is meant for retry loops around
+// CommitTransaction only, as the driver guidance recommends to retry the commit without executing the transaction
+// again.
+var CommitClassifier retry.Classifier = retry.Predicate(IsUnknownTransactionCommitResult)
+
+// IsTransientTransactionError reports whether err or any error wrapped by it carries the label
+// TransientTransactionError.
+func IsTransientTransactionError(err error) bool {
+	return hasLabel(err, TransientTransactionError)
+}
+
+// IsUnknownTransactionCommitResult reports whether err or any error wrapped by it carries the label
+// UnknownTransactionCommitResult.
+func IsUnknownTransactionCommitResult(err error) bool {
+	return hasLabel(err, UnknownTransactionCommitResult)
+}
+
+func hasLabel(err error, label string) bool {
+	return retry.ContainsRetryable(func(err error) bool {
+		var labeledErr labeled
+		return errors.As(err, &labeledErr) && labeledErr.HasErrorLabel(label)
+	})(err)
+}
@@ -0,0 +1,50 @@
+package retrymongo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// commandError mimics mongo.CommandError.
+type commandError struct {
+	labels []string
+}
+
+func (e commandError) Error() string {
+	return "command failed"
+}
+
+func (e commandError) HasErrorLabel(label string) bool {
+	return slices.Contains(e.labels, label)
+}
+
+func Test_IsTransientTransactionError(t *testing.T) {
+	assert.True(t, IsTransientTransactionError(commandError{labels: []string{TransientTransactionError}}))
+	assert.True(t, IsTransientTransactionError(fmt.Errorf("insert: %w", commandError{labels: []string{"RetryableWriteError", TransientTransactionError}})))
+	assert.True(t, IsTransientTransactionError(errors.Join(commandError{}, commandError{labels: []string{TransientTransactionError}})))
+	assert.False(t, IsTransientTransactionError(commandError{labels: []string{UnknownTransactionCommitResult}}))
+	assert.False(t, IsTransientTransactionError(assert.AnError))
+	assert.False(t, IsTransientTransactionError(nil))
+}
+
+func Test_IsUnknownTransactionCommitResult(t *testing.T) {
+	assert.True(t, IsUnknownTransactionCommitResult(commandError{labels: []string{UnknownTransactionCommitResult}}))
+	assert.False(t, IsUnknownTransactionCommitResult(commandError{labels: []string{TransientTransactionError}}))
+	assert.False(t, IsUnknownTransactionCommitResult(assert.AnError))
+}
+
+func Test_Classifiers(t *testing.T) {
+	transient := commandError{labels: []string{TransientTransactionError}}
+	unknownCommit := commandError{labels: []string{UnknownTransactionCommitResult}}
+
+	assert.Equal(t, retry.DecisionRetry, TransactionClassifier.Classify(transient))
+	assert.Equal(t, retry.DecisionAbort, TransactionClassifier.Classify(unknownCommit))
+	assert.Equal(t, retry.DecisionRetry, CommitClassifier.Classify(unknownCommit))
+	assert.Equal(t, retry.DecisionAbort, CommitClassifier.Classify(transient))
+}
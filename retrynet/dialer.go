@@ -0,0 +1,43 @@
+// Package retrynet retries establishing network connections with the Retrier of
+// github.com/cloudogu/retry-lib/retry.
+package retrynet
+
+import (
+	"context"
+	"net"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Dialer retries dialing a network address. Every attempt resolves the host name again instead of reusing the
+// addresses of the first attempt, so retries after a failover reach the new endpoints.
+type Dialer struct {
+	dialer  *net.Dialer
+	retrier *retry.Retrier
+}
+
+// NewDialer creates a Dialer establishing connections with dialer, which defaults to a zero net.Dialer. opts
+// configure the retry behaviour.
+func NewDialer(dialer *net.Dialer, opts ...retry.Option) *Dialer {
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	return &Dialer{dialer: dialer, retrier: retry.New(opts...)}
+}
+
+// DialContext connects to the address on the named network until it succeeds or the retries are exhausted. It
+// fits http.Transport.DialContext.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var conn net.Conn
+	err := d.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		// net.Dialer looks up the host on every call, so each attempt works with fresh addresses.
+		conn, err = d.dialer.DialContext(ctx, network, address)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
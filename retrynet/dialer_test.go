@@ -0,0 +1,63 @@
+package retrynet
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+func Test_Dialer_DialContext(t *testing.T) {
+	fastRetry := []retry.Option{retry.WithBackoff(retry.Constant(10 * time.Millisecond)), retry.WithMaxTries(50)}
+
+	t.Run("should connect once the endpoint is up", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			lateListener, err := net.Listen("tcp", address)
+			if err != nil {
+				return
+			}
+			defer lateListener.Close()
+			conn, _ := lateListener.Accept()
+			if conn != nil {
+				_ = conn.Close()
+			}
+		}()
+		sut := NewDialer(nil, fastRetry...)
+
+		// when
+		conn, err := sut.DialContext(context.Background(), "tcp", address)
+
+		// then
+		require.NoError(t, err)
+		_ = conn.Close()
+	})
+	t.Run("should fail when exhausted", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+		sut := NewDialer(nil, retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(2))
+
+		// when
+		conn, err := sut.DialContext(context.Background(), "tcp", address)
+
+		// then
+		var exhaustedErr *retry.ExhaustedError
+		require.ErrorAs(t, err, &exhaustedErr)
+		assert.Equal(t, 2, exhaustedErr.Attempts)
+		assert.Nil(t, conn)
+	})
+}
@@ -0,0 +1,68 @@
+package retrynet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// alertInternalError is the TLS alert a peer sends on problems unrelated to the connection's configuration.
+const alertInternalError tls.AlertError = 80
+
+// IsPermanentTLSError reports whether err is caused by a TLS misconfiguration like an invalid certificate, an
+// unknown authority or a hostname mismatch. Such errors occur again on every attempt and must not be retried.
+func IsPermanentTLSError(err error) bool {
+	var (
+		verificationErr   *tls.CertificateVerificationError
+		recordHeaderErr   tls.RecordHeaderError
+		alertErr          tls.AlertError
+		unknownAuthority  x509.UnknownAuthorityError
+		hostnameErr       x509.HostnameError
+		invalidErr        x509.CertificateInvalidError
+		constraintErr     x509.ConstraintViolationError
+		criticalExtension x509.UnhandledCriticalExtension
+		systemRootsErr    x509.SystemRootsError
+	)
+
+	switch {
+	case errors.As(err, &verificationErr),
+		errors.As(err, &recordHeaderErr),
+		errors.As(err, &unknownAuthority),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr),
+		errors.As(err, &constraintErr),
+		errors.As(err, &criticalExtension),
+		errors.As(err, &systemRootsErr):
+		return true
+	case errors.As(err, &alertErr):
+		return alertErr != alertInternalError
+	default:
+		return false
+	}
+}
+
+// IsRetriableTLSError reports whether err is a temporary problem while establishing a TLS connection, e.g. a
+// handshake timeout or a connection reset by the peer. Permanent errors, see IsPermanentTLSError, are never
+// considered retriable.
+func IsRetriableTLSError(err error) bool {
+	if err == nil || IsPermanentTLSError(err) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var alertErr tls.AlertError
+	if errors.As(err, &alertErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED)
+}
@@ -0,0 +1,89 @@
+package retrynet
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsPermanentTLSError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "other", err: assert.AnError, expected: false},
+		{name: "unknown authority", err: x509.UnknownAuthorityError{}, expected: true},
+		{name: "hostname mismatch", err: fmt.Errorf("wrapped: %w", x509.HostnameError{Host: "example.com"}), expected: true},
+		{name: "invalid certificate", err: x509.CertificateInvalidError{Reason: x509.Expired}, expected: true},
+		{name: "verification", err: &tls.CertificateVerificationError{Err: assert.AnError}, expected: true},
+		{name: "record header", err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, expected: true},
+		{name: "bad certificate alert", err: tls.AlertError(42), expected: true},
+		{name: "internal error alert", err: tls.AlertError(80), expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPermanentTLSError(tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "tls: handshake timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func Test_IsRetriableTLSError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "other", err: assert.AnError, expected: false},
+		{name: "handshake timeout", err: fmt.Errorf("wrapped: %w", timeoutError{}), expected: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, expected: true},
+		{name: "unexpected EOF", err: io.EOF, expected: true},
+		{name: "internal error alert", err: tls.AlertError(80), expected: true},
+		{name: "unknown authority", err: x509.UnknownAuthorityError{}, expected: false},
+		{name: "hostname mismatch", err: x509.HostnameError{Host: "example.com"}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsRetriableTLSError(tt.err))
+		})
+	}
+}
+
+func Test_IsPermanentTLSError_untrustedServer(t *testing.T) {
+	// given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	// when
+	_, err = http.DefaultClient.Do(req)
+
+	// then
+	require.Error(t, err)
+	assert.True(t, IsPermanentTLSError(err))
+	assert.False(t, IsRetriableTLSError(err))
+}
@@ -0,0 +1,55 @@
+// Package retrysentry reports exhausted retry loops to Sentry with github.com/getsentry/sentry-go.
+package retrysentry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// Reporter is a retry.ErrorReporter capturing the errors of exhausted retry loops as Sentry exceptions. The events
+// are tagged with the operation and carry the number of attempts in the context "retry".
+type Reporter struct {
+	hub *sentry.Hub
+}
+
+var _ retry.ErrorReporter = (*Reporter)(nil)
+
+// NewReporter creates a Reporter sending the events with hub, which defaults to sentry.CurrentHub. A hub attached to
+// the context of the retry loop, e.g. by the HTTP middleware of Sentry, takes precedence.
+func NewReporter(hub *sentry.Hub) *Reporter {
+	return &Reporter{hub: hub}
+}
+
+// ReportError implements retry.ErrorReporter.
+func (r *Reporter) ReportError(ctx context.Context, operation string, err error) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = r.hub
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		retryContext := sentry.Context{}
+		if operation != "" {
+			scope.SetTag("retry.operation", operation)
+			retryContext["operation"] = operation
+		}
+		var exhaustedErr *retry.ExhaustedError
+		if errors.As(err, &exhaustedErr) {
+			retryContext["attempts"] = exhaustedErr.Attempts
+		}
+		var noProgressErr *retry.NoProgressError
+		if errors.As(err, &noProgressErr) {
+			retryContext["attempts"] = noProgressErr.Attempts
+			retryContext["progressWindow"] = noProgressErr.Window.String()
+		}
+		scope.SetContext("retry", retryContext)
+		hub.CaptureException(err)
+	})
+}
@@ -0,0 +1,95 @@
+package retrysentry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// transportMock collects the events instead of sending them.
+type transportMock struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *transportMock) Flush(time.Duration) bool {
+	return true
+}
+
+func (t *transportMock) Configure(sentry.ClientOptions) {}
+
+func (t *transportMock) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *transportMock) Close() {}
+
+func newHub(t *testing.T) (*sentry.Hub, *transportMock) {
+	t.Helper()
+	transport := &transportMock{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: transport})
+	require.NoError(t, err)
+	return sentry.NewHub(client, sentry.NewScope()), transport
+}
+
+func Test_Reporter_ReportError(t *testing.T) {
+	t.Run("should capture exhausted retry loop", func(t *testing.T) {
+		// given
+		hub, transport := newHub(t)
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(3), retry.WithOperation("backup"), retry.WithErrorReporter(NewReporter(hub)))
+
+		// when
+		_ = sut.Do(context.Background(), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		// then
+		require.Len(t, transport.events, 1)
+		event := transport.events[0]
+		assert.Equal(t, "backup", event.Tags["retry.operation"])
+		assert.Equal(t, sentry.Context{"operation": "backup", "attempts": 3}, event.Contexts["retry"])
+		require.NotEmpty(t, event.Exception)
+		assert.Contains(t, event.Exception[len(event.Exception)-1].Value, "the maximum number of retries was reached")
+	})
+	t.Run("should prefer hub of context", func(t *testing.T) {
+		// given
+		hub, transport := newHub(t)
+		contextHub, contextTransport := newHub(t)
+		ctx := sentry.SetHubOnContext(context.Background(), contextHub)
+
+		// when
+		NewReporter(hub).ReportError(ctx, "", &retry.ExhaustedError{Attempts: 1, Err: assert.AnError})
+
+		// then
+		assert.Empty(t, transport.events)
+		assert.Len(t, contextTransport.events, 1)
+	})
+	t.Run("should not capture transient failures", func(t *testing.T) {
+		// given
+		hub, transport := newHub(t)
+		sut := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithMaxTries(3), retry.WithErrorReporter(NewReporter(hub)))
+		attempts := 0
+
+		// when
+		err := sut.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, transport.events)
+	})
+}
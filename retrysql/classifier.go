@@ -0,0 +1,70 @@
+package retrysql
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// retriableSQLStates lists the Postgres error codes reporting that the transaction may succeed if it is executed
+// again: serialization failures, detected deadlocks and the termination of the connection by an administrator, e.g.
+// during a failover.
+var retriableSQLStates = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"57P01": true, // admin_shutdown
+}
+
+// retriableMySQLErrors lists the MySQL error numbers reporting that the transaction was rolled back and may succeed
+// if it is executed again.
+var retriableMySQLErrors = map[uint64]bool{
+	1205: true, // ER_LOCK_WAIT_TIMEOUT
+	1213: true, // ER_LOCK_DEADLOCK
+}
+
+// Classifier retries the errors of IsRetriable and aborts on all others. It is the default classifier of
+// NewTxRunner.
+var Classifier retry.Classifier = retry.Predicate(IsRetriable)
+
+// IsRetriable reports whether err or any error wrapped by it is a Postgres or MySQL error that is safe to retry,
+// see IsRetriablePostgresError and IsRetriableMySQLError. It can be used with retry.OnError as well.
+func IsRetriable(err error) bool {
+	return IsRetriablePostgresError(err) || IsRetriableMySQLError(err)
+}
+
+// IsRetriablePostgresError reports whether err or any error wrapped by it carries the SQLSTATE 40001 (serialization
+// failure), 40P01 (deadlock detected) or 57P01 (admin shutdown). The state is read with the SQLState method which
+// the errors of both github.com/jackc/pgx and github.com/lib/pq provide, so that no driver needs to be imported.
+func IsRetriablePostgresError(err error) bool {
+	var stateErr interface{ SQLState() string }
+	return errors.As(err, &stateErr) && retriableSQLStates[stateErr.SQLState()]
+}
+
+// IsRetriableMySQLError reports whether err or any error wrapped by it is a MySQL error with the number 1213 (deadlock
+// found) or 1205 (lock wait timeout exceeded). As the errors of github.com/go-sql-driver/mysql do not provide a method
+// to access the number, it is read from their exported field Number so that the driver needs not be imported.
+func IsRetriableMySQLError(err error) bool {
+	return retry.ContainsRetryable(func(err error) bool {
+		number, ok := mySQLErrorNumber(err)
+		return ok && retriableMySQLErrors[number]
+	})(err)
+}
+
+func mySQLErrorNumber(err error) (uint64, bool) {
+	value := reflect.ValueOf(err)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	number := value.FieldByName("Number")
+	switch number.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return number.Uint(), true
+	default:
+		return 0, false
+	}
+}
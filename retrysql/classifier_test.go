@@ -0,0 +1,81 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// pgError mimics the errors of pgx and lib/pq.
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string {
+	return "pg error " + e.code
+}
+
+func (e *pgError) SQLState() string {
+	return e.code
+}
+
+// mySQLError mimics the errors of go-sql-driver/mysql.
+type mySQLError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mySQLError) Error() string {
+	return e.Message
+}
+
+func Test_IsRetriablePostgresError(t *testing.T) {
+	assert.True(t, IsRetriablePostgresError(&pgError{code: "40001"}))
+	assert.True(t, IsRetriablePostgresError(fmt.Errorf("commit: %w", &pgError{code: "40P01"})))
+	assert.True(t, IsRetriablePostgresError(&pgError{code: "57P01"}))
+	assert.False(t, IsRetriablePostgresError(&pgError{code: "23505"}))
+	assert.False(t, IsRetriablePostgresError(assert.AnError))
+	assert.False(t, IsRetriablePostgresError(nil))
+}
+
+func Test_IsRetriableMySQLError(t *testing.T) {
+	assert.True(t, IsRetriableMySQLError(&mySQLError{Number: 1213, Message: "Deadlock found"}))
+	assert.True(t, IsRetriableMySQLError(fmt.Errorf("update: %w", &mySQLError{Number: 1205})))
+	assert.True(t, IsRetriableMySQLError(errors.Join(assert.AnError, &mySQLError{Number: 1213})))
+	assert.False(t, IsRetriableMySQLError(&mySQLError{Number: 1062, Message: "Duplicate entry"}))
+	assert.False(t, IsRetriableMySQLError(assert.AnError))
+	assert.False(t, IsRetriableMySQLError(nil))
+}
+
+func Test_Classifier(t *testing.T) {
+	assert.Equal(t, retry.DecisionRetry, Classifier.Classify(&pgError{code: "40001"}))
+	assert.Equal(t, retry.DecisionRetry, Classifier.Classify(&mySQLError{Number: 1213}))
+	assert.Equal(t, retry.DecisionAbort, Classifier.Classify(assert.AnError))
+}
+
+func Test_TxRunner_Run_withClassifier(t *testing.T) {
+	// given
+	db, fake := openFakeDB(t, &pgError{code: "40001"}, &pgError{code: "23505"})
+	sut := NewTxRunner(db, nil, retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithClassifier(Classifier))
+
+	// when
+	err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+		return nil
+	})
+
+	// then
+	var target *pgError
+	require.ErrorAs(t, err, &target)
+	assert.Equal(t, "23505", target.code)
+	assert.Equal(t, int32(2), fake.begins.Load())
+	assert.Equal(t, int32(0), fake.commits.Load())
+}
@@ -0,0 +1,60 @@
+// Package retrysql retries database transactions with the Retrier of github.com/cloudogu/retry-lib/retry. It only
+// depends on database/sql and thus works with any driver.
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// TxRunner executes functions inside database transactions and retries the whole transaction on failure.
+type TxRunner struct {
+	db        *sql.DB
+	txOptions *sql.TxOptions
+	retrier   *retry.Retrier
+}
+
+// NewTxRunner creates a TxRunner beginning transactions on db with the given, optional txOptions. By default, it
+// only retries the errors the database reports as safe to retry, see Classifier. opts configure the retry behaviour
+// and are applied afterward, so that they may replace the classifier, e.g. with retry.WithRetriable.
+func NewTxRunner(db *sql.DB, txOptions *sql.TxOptions, opts ...retry.Option) *TxRunner {
+	return &TxRunner{
+		db:        db,
+		txOptions: txOptions,
+		retrier:   retry.New(append([]retry.Option{retry.WithClassifier(Classifier)}, opts...)...),
+	}
+}
+
+// Run executes fn inside a transaction which is committed if fn succeeds and rolled back otherwise. Failing
+// transactions are retried from the beginning, so fn must not have side effects outside the transaction.
+func (r *TxRunner) Run(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	return r.retrier.Do(ctx, func(ctx context.Context) error {
+		return runTx(ctx, r.db, r.txOptions, fn)
+	})
+}
+
+func runTx(ctx context.Context, db *sql.DB, txOptions *sql.TxOptions, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, txOptions)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	err = fn(ctx, tx)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/retry-lib/retry"
+)
+
+// fakeDriver counts transactions and fails commits as long as commitErrs contains errors.
+type fakeDriver struct {
+	mu         sync.Mutex
+	commitErrs []error
+	begins     atomic.Int32
+	commits    atomic.Int32
+	rollbacks  atomic.Int32
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.begins.Add(1)
+	return &fakeTx{driver: c.driver}, nil
+}
+
+type fakeTx struct {
+	driver *fakeDriver
+}
+
+func (t *fakeTx) Commit() error {
+	t.driver.mu.Lock()
+	defer t.driver.mu.Unlock()
+	if len(t.driver.commitErrs) > 0 {
+		err := t.driver.commitErrs[0]
+		t.driver.commitErrs = t.driver.commitErrs[1:]
+		return err
+	}
+	t.driver.commits.Add(1)
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.driver.rollbacks.Add(1)
+	return nil
+}
+
+var driverCount atomic.Int32
+
+func openFakeDB(t *testing.T, commitErrs ...error) (*sql.DB, *fakeDriver) {
+	t.Helper()
+	fake := &fakeDriver{commitErrs: commitErrs}
+	name := "retrysql-fake-" + string(rune('a'+driverCount.Add(1)))
+	sql.Register(name, fake)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, fake
+}
+
+func Test_TxRunner_Run(t *testing.T) {
+	fastRetry := []retry.Option{retry.WithBackoff(retry.Constant(time.Millisecond)), retry.WithMaxTries(3)}
+
+	t.Run("should commit transaction", func(t *testing.T) {
+		// given
+		db, fake := openFakeDB(t)
+		sut := NewTxRunner(db, nil, fastRetry...)
+
+		// when
+		err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), fake.begins.Load())
+		assert.Equal(t, int32(1), fake.commits.Load())
+	})
+	t.Run("should retry whole transaction on failed commit", func(t *testing.T) {
+		// given
+		db, fake := openFakeDB(t, &pgError{code: "40001"})
+		sut := NewTxRunner(db, nil, fastRetry...)
+		calls := 0
+
+		// when
+		err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+			calls++
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, int32(2), fake.begins.Load())
+		assert.Equal(t, int32(1), fake.commits.Load())
+	})
+	t.Run("should roll back and retry on failing function", func(t *testing.T) {
+		// given
+		db, fake := openFakeDB(t)
+		sut := NewTxRunner(db, nil, fastRetry...)
+		calls := 0
+
+		// when
+		err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+			calls++
+			if calls == 1 {
+				return &mySQLError{Number: 1213}
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), fake.rollbacks.Load())
+		assert.Equal(t, int32(1), fake.commits.Load())
+	})
+	t.Run("should not retry non-retriable driver errors", func(t *testing.T) {
+		// given
+		db, fake := openFakeDB(t)
+		sut := NewTxRunner(db, nil, fastRetry...)
+		uniqueViolation := &pgError{code: "23505"}
+
+		// when
+		err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+			return uniqueViolation
+		})
+
+		// then
+		assert.ErrorIs(t, err, uniqueViolation)
+		assert.Equal(t, int32(1), fake.begins.Load())
+		assert.Equal(t, int32(1), fake.rollbacks.Load())
+	})
+	t.Run("should let options replace classifier", func(t *testing.T) {
+		// given
+		db, fake := openFakeDB(t)
+		sut := NewTxRunner(db, nil, append(fastRetry, retry.WithRetriable(retry.AlwaysRetryFunc))...)
+
+		// when
+		err := sut.Run(context.Background(), func(ctx context.Context, tx *sql.Tx) error {
+			return assert.AnError
+		})
+
+		// then
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, int32(3), fake.begins.Load())
+	})
+}